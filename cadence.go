@@ -0,0 +1,135 @@
+package jaglogger
+
+import (
+	"sync"
+	"time"
+)
+
+// cadenceKind is the kind of cadence set by Every, EveryDuration, FirstN or Once.
+type cadenceKind int
+
+const (
+	cadenceEvery cadenceKind = iota + 1
+	cadenceEveryDuration
+	cadenceFirstN
+)
+
+// cadence is how often a call site writes its entries.
+type cadence struct {
+	kind     cadenceKind
+	n        int
+	duration time.Duration
+}
+
+// cadenceKey identifies the counter of a call site with a cadence.
+type cadenceKey struct {
+	file    string
+	line    int
+	cadence cadence
+}
+
+type cadenceCounter struct {
+	count int
+	last  time.Time
+}
+
+// cadences holds the counters of the call sites that log through a Logger with a cadence. It's shared
+// by every Logger derived from the same one.
+type cadences struct {
+	mu       sync.Mutex
+	counters map[cadenceKey]*cadenceCounter
+}
+
+// allow reports whether an entry logged at time t by the call site reported as caller should be
+// written, according to c.
+func (cs *cadences) allow(c cadence, caller Caller, t time.Time) bool {
+	key := cadenceKey{file: caller.File, line: caller.Line, cadence: c}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	if cs.counters == nil {
+		cs.counters = map[cadenceKey]*cadenceCounter{}
+	}
+	counter, ok := cs.counters[key]
+	if !ok {
+		counter = &cadenceCounter{}
+		cs.counters[key] = counter
+	}
+
+	switch c.kind {
+	case cadenceEvery:
+		counter.count++
+		return c.n <= 1 || counter.count%c.n == 1
+	case cadenceEveryDuration:
+		if counter.count > 0 && t.Sub(counter.last) < c.duration {
+			return false
+		}
+		counter.count++
+		counter.last = t
+		return true
+	default:
+		if counter.count >= c.n {
+			return false
+		}
+		counter.count++
+		return true
+	}
+}
+
+// Every returns a Logger that only writes the 1st, n+1th, 2n+1th, etc. entries logged by each call
+// site of l, which is identified by the file and line that would be reported as the caller. It
+// replaces any cadence set by Every, EveryDuration, FirstN or Once before. Like the other cadence
+// functions, it calls the method of l with the same name, and returns l unchanged if there isn't one.
+func Every(l Logger, n int) Logger {
+	if c, ok := l.(interface{ Every(int) Logger }); ok {
+		return c.Every(n)
+	}
+	return l
+}
+
+// EveryDuration returns a Logger that writes an entry logged by a call site only when at least d has
+// passed since the last entry of that call site that was written. See Every for how call sites are
+// told apart.
+func EveryDuration(l Logger, d time.Duration) Logger {
+	if c, ok := l.(interface{ EveryDuration(time.Duration) Logger }); ok {
+		return c.EveryDuration(d)
+	}
+	return l
+}
+
+// FirstN returns a Logger that only writes the first n entries logged by each call site. See Every
+// for how call sites are told apart.
+func FirstN(l Logger, n int) Logger {
+	if c, ok := l.(interface{ FirstN(int) Logger }); ok {
+		return c.FirstN(n)
+	}
+	return l
+}
+
+// Once returns a Logger that only writes the first entry logged by each call site. See Every for how
+// call sites are told apart.
+func Once(l Logger) Logger {
+	if c, ok := l.(interface{ Once() Logger }); ok {
+		return c.Once()
+	}
+	return l
+}
+
+func (l logger) Every(n int) Logger {
+	l.cadence = &cadence{kind: cadenceEvery, n: n}
+	return l
+}
+
+func (l logger) EveryDuration(d time.Duration) Logger {
+	l.cadence = &cadence{kind: cadenceEveryDuration, duration: d}
+	return l
+}
+
+func (l logger) FirstN(n int) Logger {
+	l.cadence = &cadence{kind: cadenceFirstN, n: n}
+	return l
+}
+
+func (l logger) Once() Logger {
+	return l.FirstN(1)
+}
@@ -0,0 +1,115 @@
+package jaglogger
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_logger_Cadence(t *testing.T) {
+	start := time.Date(2022, time.July, 3, 22, 5, 3, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		log  func(l Logger, now *time.Time)
+		want []string
+	}{
+		{
+			name: "Every",
+			log: func(l Logger, now *time.Time) {
+				for i := 0; i < 7; i++ {
+					Every(l, 3).Infof("every %d", i)
+				}
+			},
+			want: []string{"every 0", "every 3", "every 6"},
+		},
+		{
+			name: "Every Separate Call Sites",
+			log: func(l Logger, now *time.Time) {
+				for i := 0; i < 3; i++ {
+					Every(l, 2).Infof("a %d", i)
+					Every(l, 2).Infof("b %d", i)
+				}
+			},
+			want: []string{"a 0", "b 0", "a 2", "b 2"},
+		},
+		{
+			name: "Every Duration",
+			log: func(l Logger, now *time.Time) {
+				for i := 0; i < 6; i++ {
+					EveryDuration(l, time.Minute).Infof("tick %d", i)
+					*now = now.Add(30 * time.Second)
+				}
+			},
+			want: []string{"tick 0", "tick 2", "tick 4"},
+		},
+		{
+			name: "First N",
+			log: func(l Logger, now *time.Time) {
+				for i := 0; i < 5; i++ {
+					With(FirstN(l, 2), "i", i).Info("first")
+				}
+			},
+			want: []string{"first i=0", "first i=1"},
+		},
+		{
+			name: "Once",
+			log: func(l Logger, now *time.Time) {
+				once := Once(l)
+				for i := 0; i < 3; i++ {
+					once.Infof("once %d", i)
+				}
+				l.Infof("always")
+			},
+			want: []string{"once 0", "always"},
+		},
+		{
+			name: "Disabled Level Isn't Counted",
+			log: func(l Logger, now *time.Time) {
+				for i := 0; i < 2; i++ {
+					every := Every(l, 2)
+					every.Debugf("debug %d", i)
+					every.Infof("info %d", i)
+				}
+			},
+			want: []string{"info 0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := start
+			loggerOutput := new(bytes.Buffer)
+			l := NewLogger(
+				LogLevelInfo,
+				SetClockOpt(func() time.Time { return now }),
+				SetInfoLoggerOpt(Config{Outputs: []io.Writer{loggerOutput}, Flags: log.Lmsgprefix}),
+			)
+
+			tt.log(l, &now)
+			got := strings.Split(strings.TrimSuffix(strings.ReplaceAll(loggerOutput.String(), "[INFO]", ""), "\n"), "\n")
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func logOnceThroughHelper(l Logger, msg string) {
+	Helper(l)
+	Once(l).Info(msg)
+}
+
+func Test_logger_CadenceHelper(t *testing.T) {
+	loggerOutput := new(bytes.Buffer)
+	l := NewLogger(LogLevelInfo, SetInfoLoggerOpt(Config{Outputs: []io.Writer{loggerOutput}, Flags: log.Lmsgprefix}))
+
+	// The call site is the caller of the helper, so each of these is logged once
+	for i := 0; i < 2; i++ {
+		logOnceThroughHelper(l, "first")
+		logOnceThroughHelper(l, "second")
+	}
+	assert.Equal(t, "[INFO]first\n[INFO]second\n", loggerOutput.String())
+}
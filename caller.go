@@ -0,0 +1,238 @@
+package jaglogger
+
+import (
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+// CallerFormat determines how the caller of a log entry is reported. A file format (CallerLongFile,
+// CallerShortFile, CallerModuleFile or CallerTrimmedFile) may be combined with CallerFunction to
+// report both, e.g. CallerShortFile|CallerFunction.
+type CallerFormat int
+
+const (
+	// CallerLongFile reports the full file path and line number: /a/b/c/d.go:23
+	CallerLongFile CallerFormat = 1 << iota
+	// CallerShortFile reports the file name and line number: d.go:23
+	CallerShortFile
+	// CallerModuleFile reports the file path relative to the root of its module: c/d.go:23
+	// Files of packages outside the main module are reported by their import path: example.com/c/d.go:23
+	CallerModuleFile
+	// CallerTrimmedFile reports the full file path, with the first matching prefix set by
+	// SetCallerTrimPrefixesOpt removed.
+	CallerTrimmedFile
+	// CallerFunction reports the package qualified name of the calling function: example.com/c.(*T).Method
+	CallerFunction
+	// CallerDisabled disables the lookup of the caller entirely.
+	CallerDisabled
+)
+
+const callerFileFormats = CallerLongFile | CallerShortFile | CallerModuleFile | CallerTrimmedFile
+
+// callerFormatFromFlags returns the CallerFormat equivalent of the log.Llongfile and log.Lshortfile
+// flags. Like the log package, log.Lshortfile takes precedence over log.Llongfile.
+func callerFormatFromFlags(flags int) CallerFormat {
+	switch {
+	case flags&log.Lshortfile != 0:
+		return CallerShortFile
+	case flags&log.Llongfile != 0:
+		return CallerLongFile
+	default:
+		return CallerDisabled
+	}
+}
+
+// Caller holds the information about where a log entry was made.
+type Caller struct {
+	File     string
+	Line     int
+	Function string
+	// Defined is false when the caller couldn't be determined
+	Defined bool
+}
+
+// lookupCaller returns the caller at the given stack depth, skipping over any functions marked as
+// helpers. Like runtime.Caller, a skip of 0 is the caller of lookupCaller.
+func lookupCaller(skip int, helpers *helperFuncs) Caller {
+	if !helpers.any() {
+		var pcs [1]uintptr
+		// Skip runtime.Callers and lookupCaller
+		if runtime.Callers(skip+2, pcs[:]) == 0 {
+			return Caller{}
+		}
+		frame, _ := runtime.CallersFrames(pcs[:]).Next()
+		return callerFromFrame(frame)
+	}
+
+	var pcs [32]uintptr
+	n := runtime.Callers(skip+2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !more || !helpers.contains(frame.Function) {
+			return callerFromFrame(frame)
+		}
+	}
+}
+
+func callerFromFrame(frame runtime.Frame) Caller {
+	return Caller{
+		File:     frame.File,
+		Line:     frame.Line,
+		Function: frame.Function,
+		Defined:  frame.PC != 0,
+	}
+}
+
+// helperFuncs is the set of functions that have been marked as helpers by Helper.
+type helperFuncs struct {
+	mu    sync.RWMutex
+	names map[string]struct{}
+}
+
+// add marks the function that is skip frames up the stack from the caller of add as a helper.
+func (h *helperFuncs) add(skip int) {
+	var pcs [1]uintptr
+	if runtime.Callers(skip+2, pcs[:]) == 0 {
+		return
+	}
+	frame, _ := runtime.CallersFrames(pcs[:]).Next()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.names == nil {
+		h.names = map[string]struct{}{}
+	}
+	h.names[frame.Function] = struct{}{}
+}
+
+func (h *helperFuncs) any() bool {
+	if h == nil {
+		return false
+	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.names) > 0
+}
+
+func (h *helperFuncs) contains(function string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	_, ok := h.names[function]
+	return ok
+}
+
+// callerPath returns the path of the caller's file according to format.
+func callerPath(c Caller, format CallerFormat, trimPrefixes []string) string {
+	switch {
+	case format&CallerShortFile != 0:
+		return path.Base(c.File)
+	case format&CallerModuleFile != 0:
+		return moduleRelativePath(c.File, c.Function)
+	case format&CallerTrimmedFile != 0:
+		for _, prefix := range trimPrefixes {
+			if strings.HasPrefix(c.File, prefix) {
+				return strings.TrimPrefix(c.File[len(prefix):], "/")
+			}
+		}
+		return c.File
+	default:
+		return c.File
+	}
+}
+
+// funcPackage returns the import path of the package that the given function belongs to.
+func funcPackage(function string) string {
+	lastSlash := strings.LastIndexByte(function, '/')
+	if lastSlash < 0 {
+		lastSlash = 0
+	}
+	pkg := function
+	if dot := strings.IndexByte(function[lastSlash:], '.'); dot >= 0 {
+		pkg = function[:lastSlash+dot]
+	}
+
+	// The runtime escapes dots in the last element of the import path
+	pkg = strings.ReplaceAll(pkg, "%2e", ".")
+	return strings.TrimSuffix(pkg, "_test")
+}
+
+var (
+	mainModuleOnce sync.Once
+	mainModulePath string
+)
+
+// mainModule returns the module path of the main module, or an empty string if it's unknown.
+func mainModule() string {
+	mainModuleOnce.Do(func() {
+		if info, ok := debug.ReadBuildInfo(); ok {
+			mainModulePath = info.Main.Path
+		}
+	})
+	return mainModulePath
+}
+
+// moduleRelativePath returns the path of file relative to the root of its module. The import path of
+// the function's package is used to determine where that is, so it works without access to the source.
+// Since the import path of a main package doesn't say where it lives in the module, the module root is
+// found by looking for a go.mod file instead.
+func moduleRelativePath(file, function string) string {
+	module := mainModule()
+	// Builds using -trimpath already have the module path in place of the module root
+	if module != "" && strings.HasPrefix(file, module+"/") {
+		return file[len(module)+1:]
+	}
+
+	base := path.Base(file)
+	pkg := funcPackage(function)
+	switch {
+	case pkg == "main":
+		if root := findModuleRoot(path.Dir(file)); root != "" {
+			if rel, err := filepath.Rel(root, file); err == nil {
+				return filepath.ToSlash(rel)
+			}
+		}
+		return base
+	case module != "" && pkg == module:
+		return base
+	case module != "" && strings.HasPrefix(pkg, module+"/"):
+		return pkg[len(module)+1:] + "/" + base
+	case pkg != "":
+		return pkg + "/" + base
+	default:
+		return base
+	}
+}
+
+// moduleRoots caches the result of findModuleRoot by directory.
+var moduleRoots sync.Map
+
+// findModuleRoot returns the closest directory, starting with dir and moving up, that contains a
+// go.mod file. An empty string is returned if there is none.
+func findModuleRoot(dir string) string {
+	if root, ok := moduleRoots.Load(dir); ok {
+		return root.(string)
+	}
+
+	root := ""
+	for d := dir; ; {
+		if _, err := os.Stat(filepath.Join(d, "go.mod")); err == nil {
+			root = d
+			break
+		}
+		parent := filepath.Dir(d)
+		if parent == d {
+			break
+		}
+		d = parent
+	}
+
+	moduleRoots.Store(dir, root)
+	return root
+}
@@ -0,0 +1,227 @@
+package jaglogger
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_funcPackage(t *testing.T) {
+	tests := []struct {
+		name     string
+		function string
+		want     string
+	}{
+		{
+			name:     "Function",
+			function: "github.com/williabk198/jaglogger.NewLogger",
+			want:     "github.com/williabk198/jaglogger",
+		},
+		{
+			name:     "Method",
+			function: "github.com/williabk198/jaglogger.(*lineWriter).Write",
+			want:     "github.com/williabk198/jaglogger",
+		},
+		{
+			name:     "Closure",
+			function: "github.com/williabk198/jaglogger.Test_funcPackage.func1",
+			want:     "github.com/williabk198/jaglogger",
+		},
+		{
+			name:     "Main Package",
+			function: "main.main",
+			want:     "main",
+		},
+		{
+			name:     "Escaped Dot",
+			function: "gopkg.in/yaml%2ev3.Marshal",
+			want:     "gopkg.in/yaml.v3",
+		},
+		{
+			name:     "External Test Package",
+			function: "github.com/williabk198/jaglogger_test.TestExample",
+			want:     "github.com/williabk198/jaglogger",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := funcPackage(tt.function)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_callerPath(t *testing.T) {
+	type args struct {
+		c            Caller
+		format       CallerFormat
+		trimPrefixes []string
+	}
+
+	moduleCaller := Caller{
+		File:     "/home/ci/work/jaglogger/sub/file.go",
+		Function: "github.com/williabk198/jaglogger/sub.Func",
+		Defined:  true,
+	}
+	dependencyCaller := Caller{
+		File:     "/home/ci/go/pkg/mod/github.com/stretchr/testify@v1.8.0/assert/assertions.go",
+		Function: "github.com/stretchr/testify/assert.Equal",
+		Defined:  true,
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "Long File",
+			args: args{c: moduleCaller, format: CallerLongFile},
+			want: "/home/ci/work/jaglogger/sub/file.go",
+		},
+		{
+			name: "Short File",
+			args: args{c: moduleCaller, format: CallerShortFile},
+			want: "file.go",
+		},
+		{
+			name: "Module File",
+			args: args{c: moduleCaller, format: CallerModuleFile},
+			want: "sub/file.go",
+		},
+		{
+			name: "Module File Trimpath Build",
+			args: args{
+				c:      Caller{File: "github.com/williabk198/jaglogger/sub/file.go", Function: "main.main", Defined: true},
+				format: CallerModuleFile,
+			},
+			want: "sub/file.go",
+		},
+		{
+			name: "Module File Dependency",
+			args: args{c: dependencyCaller, format: CallerModuleFile},
+			want: "github.com/stretchr/testify/assert/assertions.go",
+		},
+		{
+			name: "Trimmed File",
+			args: args{c: moduleCaller, format: CallerTrimmedFile, trimPrefixes: []string{"/home/ci/go/", "/home/ci/work"}},
+			want: "jaglogger/sub/file.go",
+		},
+		{
+			name: "Trimmed File No Match",
+			args: args{c: moduleCaller, format: CallerTrimmedFile, trimPrefixes: []string{"/build/"}},
+			want: "/home/ci/work/jaglogger/sub/file.go",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := callerPath(tt.args.c, tt.args.format, tt.args.trimPrefixes)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_logger_CallerFormat(t *testing.T) {
+	type args struct {
+		opts []Option
+		conf Config
+	}
+
+	tests := []struct {
+		name      string
+		args      args
+		wantMatch *regexp.Regexp
+	}{
+		{
+			name:      "Short File Flag",
+			args:      args{conf: Config{Flags: log.Lshortfile}},
+			wantMatch: regexp.MustCompile(`^\[INFO\]caller_test\.go\:\d+\:\stest\n$`),
+		},
+		{
+			name:      "Module File And Function",
+			args:      args{conf: Config{Flags: log.Lmsgprefix, Caller: CallerModuleFile | CallerFunction}},
+			wantMatch: regexp.MustCompile(`^caller_test\.go\:\d+\sgithub\.com\/williabk198\/jaglogger\.Test_logger_CallerFormat\.func\d+\:\s\[INFO\]test\n$`),
+		},
+		{
+			name:      "Function Only",
+			args:      args{conf: Config{Flags: log.Lmsgprefix, Caller: CallerFunction}},
+			wantMatch: regexp.MustCompile(`^github\.com\/williabk198\/jaglogger\.Test_logger_CallerFormat\.func\d+\:\s\[INFO\]test\n$`),
+		},
+		{
+			name:      "Default Caller",
+			args:      args{opts: []Option{SetDefaultCallerOpt(CallerShortFile)}, conf: Config{Flags: log.Lmsgprefix}},
+			wantMatch: regexp.MustCompile(`^caller_test\.go\:\d+\:\s\[INFO\]test\n$`),
+		},
+		{
+			name:      "Caller Disabled",
+			args:      args{conf: Config{Flags: log.Llongfile, Caller: CallerDisabled}},
+			wantMatch: regexp.MustCompile(`^\[INFO\]test\n$`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loggerOutput := new(bytes.Buffer)
+			tt.args.conf.Outputs = []io.Writer{loggerOutput}
+			l := NewLogger(LogLevelInfo, append(tt.args.opts, SetInfoLoggerOpt(tt.args.conf))...)
+
+			l.Info("test")
+			got := loggerOutput.String()
+			assert.Regexp(t, tt.wantMatch, got)
+		})
+	}
+}
+
+func logThroughWrapper(l Logger, msg string) {
+	AddCallerSkip(l, 1).Info(msg)
+}
+
+func logThroughHelper(l Logger, msg string) {
+	Helper(l)
+	l.Info(msg)
+}
+
+func logThroughNestedHelper(l Logger, msg string) {
+	Helper(l)
+	logThroughHelper(l, msg)
+}
+
+func Test_logger_AddCallerSkip(t *testing.T) {
+	loggerOutput := new(bytes.Buffer)
+	l := NewLogger(LogLevelInfo, SetInfoLoggerOpt(Config{Outputs: []io.Writer{loggerOutput}, Flags: log.Lmsgprefix, Caller: CallerFunction}))
+
+	logThroughWrapper(l, "test")
+	assert.Equal(t, "github.com/williabk198/jaglogger.Test_logger_AddCallerSkip: [INFO]test\n", loggerOutput.String())
+
+	loggerOutput.Reset()
+	AddCallerSkip(AddCallerSkip(l, 1), -1).Info("test")
+	assert.Equal(t, "github.com/williabk198/jaglogger.Test_logger_AddCallerSkip: [INFO]test\n", loggerOutput.String())
+}
+
+func Test_logger_Helper(t *testing.T) {
+	tests := []struct {
+		name    string
+		logFunc func(Logger, string)
+	}{
+		{
+			name:    "Helper",
+			logFunc: logThroughHelper,
+		},
+		{
+			name:    "Nested Helpers",
+			logFunc: logThroughNestedHelper,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loggerOutput := new(bytes.Buffer)
+			l := NewLogger(LogLevelInfo, SetInfoLoggerOpt(Config{Outputs: []io.Writer{loggerOutput}, Flags: log.Lmsgprefix, Caller: CallerFunction}))
+
+			tt.logFunc(l, "test")
+			assert.Regexp(t, regexp.MustCompile(`^github\.com\/williabk198\/jaglogger\.Test_logger_Helper\.func\d+\:\s\[INFO\]test\n$`), loggerOutput.String())
+		})
+	}
+}
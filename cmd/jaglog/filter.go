@@ -0,0 +1,139 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/williabk198/jaglogger"
+)
+
+// filterOptions holds the flags that decide which entries are kept.
+type filterOptions struct {
+	minLevel string
+	maxLevel string
+	since    string
+	until    string
+	caller   string
+	msg      string
+}
+
+func (o *filterOptions) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.minLevel, "min-level", "", "only show entries at or above this log level")
+	fs.StringVar(&o.maxLevel, "max-level", "", "only show entries at or below this log level")
+	fs.StringVar(&o.since, "since", "", "only show entries at or after this time; an RFC 3339 time, a date, or a duration before now like 1h30m")
+	fs.StringVar(&o.until, "until", "", "only show entries before this time, in the same format as -since")
+	fs.StringVar(&o.caller, "caller", "", "only show entries whose caller, written as file:line function, matches this regular expression")
+	fs.StringVar(&o.msg, "msg", "", "only show entries whose message matches this regular expression")
+}
+
+// filter decides which entries are kept.
+type filter struct {
+	minLevel, maxLevel jaglogger.LogLevel
+	since, until       time.Time
+	caller, msg        *regexp.Regexp
+}
+
+// newFilter parses the options. Relative times are relative to now.
+func (o *filterOptions) newFilter(now time.Time) (*filter, error) {
+	var f filter
+	var err error
+	if o.minLevel != "" {
+		if f.minLevel, err = jaglogger.ParseLogLevel(o.minLevel); err != nil {
+			return nil, err
+		}
+	}
+	if o.maxLevel != "" {
+		if f.maxLevel, err = jaglogger.ParseLogLevel(o.maxLevel); err != nil {
+			return nil, err
+		}
+	}
+	if o.since != "" {
+		if f.since, err = parseTimeArg(o.since, now); err != nil {
+			return nil, fmt.Errorf("invalid -since: %w", err)
+		}
+	}
+	if o.until != "" {
+		if f.until, err = parseTimeArg(o.until, now); err != nil {
+			return nil, fmt.Errorf("invalid -until: %w", err)
+		}
+	}
+	if o.caller != "" {
+		if f.caller, err = regexp.Compile(o.caller); err != nil {
+			return nil, fmt.Errorf("invalid -caller: %w", err)
+		}
+	}
+	if o.msg != "" {
+		if f.msg, err = regexp.Compile(o.msg); err != nil {
+			return nil, fmt.Errorf("invalid -msg: %w", err)
+		}
+	}
+	return &f, nil
+}
+
+// timeArgLayouts are the layouts accepted by parseTimeArg, other than durations.
+var timeArgLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006/01/02 15:04:05",
+	"2006-01-02",
+}
+
+// parseTimeArg parses a time given on the command line. Times without a time zone are in local time.
+func parseTimeArg(s string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	for _, layout := range timeArgLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is neither a time nor a duration", s)
+}
+
+// active reports whether any entries get filtered out.
+func (f *filter) active() bool {
+	return f.minLevel != 0 || f.maxLevel != 0 || !f.since.IsZero() || !f.until.IsZero() || f.caller != nil || f.msg != nil
+}
+
+// match reports whether e is kept.
+func (f *filter) match(e *jaglogger.Entry) bool {
+	if f.minLevel != 0 && e.Level < f.minLevel {
+		return false
+	}
+	if f.maxLevel != 0 && (e.Level == 0 || e.Level > f.maxLevel) {
+		return false
+	}
+	if !f.since.IsZero() && e.Time.Before(f.since) {
+		return false
+	}
+	if !f.until.IsZero() && !e.Time.Before(f.until) {
+		return false
+	}
+	if f.caller != nil && !f.caller.MatchString(callerString(e.Caller)) {
+		return false
+	}
+	if f.msg != nil && !f.msg.MatchString(e.Message) {
+		return false
+	}
+	return true
+}
+
+// callerString formats c as file:line function, leaving out the parts that aren't known.
+func callerString(c jaglogger.Caller) string {
+	if !c.Defined {
+		return ""
+	}
+	s := c.Function
+	if c.File != "" {
+		s = c.File + ":" + strconv.Itoa(c.Line)
+		if c.Function != "" {
+			s += " " + c.Function
+		}
+	}
+	return s
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"io"
+	"os"
+	"time"
+)
+
+// followReader reads a file as it grows, like tail -f. When the file is truncated it's read from the
+// start again, and when it's replaced, such as by log rotation, the new file is read.
+type followReader struct {
+	name     string
+	f        *os.File
+	offset   int64
+	interval time.Duration
+	// Read returns io.EOF once done is closed. A nil channel means reading never ends.
+	done <-chan struct{}
+}
+
+func openFollowReader(name string, interval time.Duration, done <-chan struct{}) (*followReader, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &followReader{name: name, f: f, interval: interval, done: done}, nil
+}
+
+func (r *followReader) Read(p []byte) (int, error) {
+	for {
+		n, err := r.f.Read(p)
+		r.offset += int64(n)
+		if n > 0 {
+			return n, nil
+		}
+		if err != nil && err != io.EOF {
+			return 0, err
+		}
+
+		if err := r.checkFile(); err != nil {
+			return 0, err
+		}
+		select {
+		case <-r.done:
+			return 0, io.EOF
+		case <-time.After(r.interval):
+		}
+	}
+}
+
+// checkFile starts reading from the start of the file if it was truncated, or a new file if it was replaced.
+func (r *followReader) checkFile() error {
+	current, err := r.f.Stat()
+	if err != nil {
+		return err
+	}
+	if current.Size() < r.offset {
+		r.offset = 0
+		_, err := r.f.Seek(0, io.SeekStart)
+		return err
+	}
+
+	// A missing file is most likely in the middle of being replaced
+	latest, err := os.Stat(r.name)
+	if err != nil || os.SameFile(current, latest) {
+		return nil
+	}
+	f, err := os.Open(r.name)
+	if err != nil {
+		return nil
+	}
+	r.f.Close()
+	r.f, r.offset = f, 0
+	return nil
+}
+
+func (r *followReader) Close() error {
+	return r.f.Close()
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// syncBuffer is a bytes.Buffer that can be written and read concurrently.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestFollowReader(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.log")
+	assert.NoError(t, os.WriteFile(name, []byte("first\n"), 0o600))
+
+	done := make(chan struct{})
+	r, err := openFollowReader(name, time.Millisecond, done)
+	assert.NoError(t, err)
+	defer r.Close()
+
+	got := new(syncBuffer)
+	copied := make(chan error)
+	go func() {
+		_, err := io.Copy(got, r)
+		copied <- err
+	}()
+
+	waitFor := func(want string) {
+		t.Helper()
+		assert.Eventually(t, func() bool { return got.String() == want }, 5*time.Second, time.Millisecond)
+	}
+	waitFor("first\n")
+
+	f, err := os.OpenFile(name, os.O_APPEND|os.O_WRONLY, 0)
+	assert.NoError(t, err)
+	_, err = f.WriteString("second\n")
+	assert.NoError(t, err)
+	waitFor("first\nsecond\n")
+
+	// After truncation, the file is read from the start again
+	assert.NoError(t, f.Truncate(0))
+	_, err = f.WriteString("new\n")
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+	waitFor("first\nsecond\nnew\n")
+
+	close(done)
+	assert.NoError(t, <-copied)
+}
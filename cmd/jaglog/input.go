@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/williabk198/jaglogger"
+)
+
+// inputOptions holds the flags that decide how the logs are read.
+type inputOptions struct {
+	format    string
+	textFlags string
+}
+
+func (o *inputOptions) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.format, "in", "auto", "format of the logs: auto, text, logfmt or json")
+	fs.StringVar(&o.textFlags, "text-flags", "date,time,longfile",
+		"comma separated log flags the text format logs were written with: date, time, microseconds, utc, longfile, shortfile and msgprefix")
+}
+
+func (o *inputOptions) validate() error {
+	switch o.format {
+	case "auto", "text", "logfmt", "json":
+	default:
+		return fmt.Errorf("unknown input format %q", o.format)
+	}
+	_, err := parseFlagNames(o.textFlags)
+	return err
+}
+
+// detectBufferSize is the size of the buffer used to detect the format, which is the longest first
+// line that can be detected.
+const detectBufferSize = 64 * 1024
+
+// newScanner returns a Scanner for r. When the format is auto, it's detected from the first line.
+// When lineByLine is set, text format entries are parsed one line at a time, rather than waiting for
+// the next entry to find out whether a message continues on the next line.
+func (o *inputOptions) newScanner(r io.Reader, lineByLine bool) jaglogger.Scanner {
+	format := o.format
+	if format == "auto" {
+		br := bufio.NewReaderSize(r, detectBufferSize)
+		format = detectFormat(br)
+		r = br
+	}
+
+	switch format {
+	case "json":
+		return jaglogger.NewJSONScanner(r)
+	case "logfmt":
+		return jaglogger.NewLogfmtScanner(r)
+	default:
+		flags, _ := parseFlagNames(o.textFlags)
+		parser := jaglogger.TextParser{Flags: flags}
+		if lineByLine {
+			return jaglogger.NewLineScanner(r, parser.Parse)
+		}
+		return jaglogger.NewTextScanner(r, parser)
+	}
+}
+
+// detectFormat guesses the format of the logs from the first line that isn't blank, without consuming it.
+// It only waits for as much input as it needs, so that a pipe or a followed file that's still being
+// written isn't waited on until it ends.
+func detectFormat(br *bufio.Reader) string {
+	for {
+		buf, _ := br.Peek(br.Buffered())
+		if line, ok := firstLine(buf, br.Buffered() == br.Size()); ok {
+			return lineFormat(line)
+		}
+		// Wait for at least one more byte than what's already buffered
+		if _, err := br.Peek(br.Buffered() + 1); err != nil {
+			// The whole input is buffered, so its last line is complete
+			buf, _ := br.Peek(br.Buffered())
+			if line, ok := firstLine(buf, true); ok {
+				return lineFormat(line)
+			}
+			return "text"
+		}
+	}
+}
+
+// firstLine returns the first line of buf that isn't blank. The last line of buf is only used if
+// it's complete.
+func firstLine(buf []byte, complete bool) (string, bool) {
+	lines := bytes.Split(buf, []byte("\n"))
+	if !complete {
+		lines = lines[:len(lines)-1]
+	}
+	for _, line := range lines {
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 {
+			return string(line), true
+		}
+	}
+	return "", false
+}
+
+func lineFormat(line string) string {
+	if strings.HasPrefix(line, "{") && strings.HasSuffix(line, "}") {
+		return "json"
+	}
+	if e, err := jaglogger.ParseLogfmt(line); err == nil && (e.Level != 0 || e.Message != "") {
+		return "logfmt"
+	}
+	return "text"
+}
+
+var flagNames = map[string]int{
+	"date":         log.Ldate,
+	"time":         log.Ltime,
+	"microseconds": log.Lmicroseconds,
+	"utc":          log.LUTC,
+	"longfile":     log.Llongfile,
+	"shortfile":    log.Lshortfile,
+	"msgprefix":    log.Lmsgprefix,
+}
+
+// parseFlagNames parses a comma separated list of log flag names.
+func parseFlagNames(names string) (int, error) {
+	var flags int
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		flag, ok := flagNames[strings.ToLower(name)]
+		if !ok {
+			return 0, fmt.Errorf("unknown log flag %q", name)
+		}
+		flags |= flag
+	}
+	if flags == 0 {
+		// A flags value of 0 means the defaults to jaglogger, so msgprefix is used to mean "none"
+		flags = log.Lmsgprefix
+	}
+	return flags, nil
+}
+
+// openInput opens the named file, or returns stdin if the name is "-".
+func openInput(name string, stdin io.Reader) (io.ReadCloser, error) {
+	if name == "-" {
+		return io.NopCloser(stdin), nil
+	}
+	return os.Open(name)
+}
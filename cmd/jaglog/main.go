@@ -0,0 +1,67 @@
+// Command jaglog reads the logs written by jaglogger, in the text, logfmt or JSON format, and
+// pretty-prints, filters, converts, merges or summarizes them.
+//
+// Usage:
+//
+//	jaglog [view] [flags] [file ...]
+//	jaglog merge [flags] file ...
+//	jaglog stats [flags] [file ...]
+//
+// The view command, which is the default, pretty-prints, filters and converts logs. The merge command
+// does the same for several files at once, interleaving their entries by time. The stats command
+// reports how many entries there are per log level over time, and the most common callers and messages.
+//
+// With no files, or a file named "-", the logs are read from the standard input.
+// Run "jaglog <command> -h" for the list of flags.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// command is a jaglog subcommand. It returns the exit code of the program.
+type command func(args []string, stdin io.Reader, stdout, stderr io.Writer) int
+
+// commands holds the subcommands by name. Without one, the view command is run.
+var commands = map[string]command{
+	"view":  runView,
+	"merge": runMerge,
+	"stats": runStats,
+}
+
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) > 0 {
+		if cmd, ok := commands[args[0]]; ok {
+			return cmd(args[1:], stdin, stdout, stderr)
+		}
+	}
+	return runView(args, stdin, stdout, stderr)
+}
+
+// parseExitCode returns the exit code for an error returned by flag.FlagSet.Parse.
+func parseExitCode(err error) int {
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	return exitUsage
+}
+
+// errorf writes an error message to stderr and returns exitError.
+func errorf(stderr io.Writer, format string, v ...any) int {
+	fmt.Fprintf(stderr, "jaglog: "+format+"\n", v...)
+	return exitError
+}
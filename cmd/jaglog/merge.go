@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/williabk198/jaglogger"
+)
+
+// mergeCommand interleaves several log files by time.
+type mergeCommand struct {
+	input  inputOptions
+	filter filterOptions
+	output outputOptions
+	tag    bool
+
+	// now is replaced by tests
+	now func() time.Time
+}
+
+func runMerge(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	cmd := mergeCommand{now: time.Now}
+	return cmd.run(args, stdin, stdout, stderr)
+}
+
+func (c *mergeCommand) run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("jaglog merge", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: jaglog merge [flags] file ...")
+		fmt.Fprintln(fs.Output(), "Merges jaglogger logs by time, tagging each entry with the file it came from. A file named - is the standard input.")
+		fs.PrintDefaults()
+	}
+	c.input.register(fs)
+	c.filter.register(fs)
+	c.output.register(fs)
+	fs.BoolVar(&c.tag, "tag", true, "tag each entry with the file it came from")
+	if err := fs.Parse(args); err != nil {
+		return parseExitCode(err)
+	}
+
+	names := fs.Args()
+	if len(names) == 0 {
+		fs.Usage()
+		return exitUsage
+	}
+	if err := c.input.validate(); err != nil {
+		fmt.Fprintln(stderr, "jaglog:", err)
+		return exitUsage
+	}
+	if err := c.output.validate(); err != nil {
+		fmt.Fprintln(stderr, "jaglog:", err)
+		return exitUsage
+	}
+	f, err := c.filter.newFilter(c.now())
+	if err != nil {
+		fmt.Fprintln(stderr, "jaglog:", err)
+		return exitUsage
+	}
+
+	sources := make([]jaglogger.MergeSource, len(names))
+	for i, name := range names {
+		r, err := openInput(name, stdin)
+		if err != nil {
+			return errorf(stderr, "%s", err)
+		}
+		defer r.Close()
+		sources[i] = jaglogger.MergeSource{Name: name, Scanner: c.input.newScanner(r, false)}
+	}
+
+	s := jaglogger.NewMergeScanner(sources...)
+	p := newEntryPrinter(f, c.output.newWriter(stdout), stdout)
+	for s.Scan() {
+		source := ""
+		if c.tag {
+			source = s.Source()
+		}
+		p.print(s, source)
+	}
+	if err := s.Err(); err != nil {
+		return errorf(stderr, "%s", err)
+	}
+	return exitOK
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunMerge(t *testing.T) {
+	dir := t.TempDir()
+	errLog := filepath.Join(dir, "err.log")
+	outLog := filepath.Join(dir, "out.log")
+	assert.NoError(t, os.WriteFile(errLog, []byte(
+		"[ERROR]2022/07/03 22:05:02 /app/db.go:23: query failed\n"+
+			"  retrying\n"+
+			"[WARNING]2022/07/03 22:05:04 /app/db.go:30: slow query\n",
+	), 0o600))
+	assert.NoError(t, os.WriteFile(outLog, []byte(
+		`{"time":"2022-07-03T22:05:01Z","level":"info","msg":"started"}`+"\n"+
+			`{"time":"2022-07-03T22:05:04Z","level":"info","msg":"request"}`+"\n",
+	), 0o600))
+
+	tests := []struct {
+		name     string
+		args     []string
+		stdin    string
+		want     string
+		wantCode int
+	}{
+		{
+			name: "Merge",
+			args: []string{"-format", "logfmt", "-out-flags", "", errLog, outLog},
+			want: "level=info msg=started source=" + outLog + "\n" +
+				"level=error msg=\"query failed\\n  retrying\" source=" + errLog + "\n" +
+				"level=warning msg=\"slow query\" source=" + errLog + "\n" +
+				"level=info msg=request source=" + outLog + "\n",
+		},
+		{
+			name:  "Untagged With Stdin",
+			args:  []string{"-tag=false", "-format", "logfmt", "-out-flags", "", "-min-level", "info", "-", outLog},
+			stdin: "level=debug time=2022-07-03T22:05:00Z msg=ignored\nnot an entry\nlevel=notice time=2022-07-03T22:05:02Z msg=stdin\n",
+			want:  "level=info msg=started\nlevel=notice msg=stdin\nlevel=info msg=request\n",
+		},
+		{
+			name:  "Unparsable Lines Tagged",
+			args:  []string{"-format", "logfmt", "-out-flags", "", "-", outLog},
+			stdin: "level=info time=2022-07-03T22:05:03Z msg=ok\nmsg=\"broken\n",
+			want: "level=info msg=started source=" + outLog + "\n" +
+				"level=info msg=ok source=-\n" +
+				"-: msg=\"broken\n" +
+				"level=info msg=request source=" + outLog + "\n",
+		},
+		{
+			name:     "No Files",
+			args:     nil,
+			wantCode: exitUsage,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+			code := run(append([]string{"merge"}, tt.args...), strings.NewReader(tt.stdin), stdout, stderr)
+			assert.Equal(t, tt.wantCode, code, stderr.String())
+			if tt.wantCode == exitOK {
+				assert.Equal(t, tt.want, stdout.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/williabk198/jaglogger"
+)
+
+// outputOptions holds the flags that decide how the logs are written.
+type outputOptions struct {
+	format string
+	color  string
+	flags  string
+}
+
+func (o *outputOptions) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.format, "format", "console", "output format: console, text, logfmt or json")
+	fs.StringVar(&o.color, "color", "auto", "colorize the console format: auto, always or never")
+	fs.StringVar(&o.flags, "out-flags", "date,time,longfile", "comma separated log flags to write the entries with, as with -text-flags")
+}
+
+var outputFormats = map[string]jaglogger.Format{
+	"console": jaglogger.FormatConsole,
+	"text":    jaglogger.FormatText,
+	"logfmt":  jaglogger.FormatLogfmt,
+	"json":    jaglogger.FormatJSON,
+}
+
+func (o *outputOptions) validate() error {
+	if _, ok := outputFormats[o.format]; !ok {
+		return fmt.Errorf("unknown output format %q", o.format)
+	}
+	switch o.color {
+	case "auto", "always", "never":
+	default:
+		return fmt.Errorf("unknown color mode %q", o.color)
+	}
+	_, err := parseFlagNames(o.flags)
+	return err
+}
+
+// newWriter returns an EntryWriter that writes every entry to w.
+func (o *outputOptions) newWriter(w io.Writer) *jaglogger.EntryWriter {
+	flags, _ := parseFlagNames(o.flags)
+	opts := []jaglogger.Option{
+		jaglogger.SetDefaultFormatOpt(outputFormats[o.format]),
+		jaglogger.SetDefaultFlagsOpt(flags),
+		jaglogger.SetDefaultErrorOutputsOpt([]io.Writer{w}),
+		jaglogger.SetDefaultNonErrorOutputOpt([]io.Writer{w}),
+	}
+	// With auto, jaglogger decides from the FORCE_COLOR and NO_COLOR variables and the output
+	switch o.color {
+	case "always":
+		opts = append(opts, jaglogger.SetColorOpt(true))
+	case "never":
+		opts = append(opts, jaglogger.SetColorOpt(false))
+	}
+	return jaglogger.NewEntryWriter(jaglogger.LogLevelDebug, opts...)
+}
+
+// sourceKey is the key of the field that tells which file a merged entry came from.
+const sourceKey = "source"
+
+// entryPrinter writes the entries that match a filter.
+type entryPrinter struct {
+	filter *filter
+	writer *jaglogger.EntryWriter
+	stdout io.Writer
+	// skipped holds the sources whose last entry wasn't written. Lines that can't be parsed are copied
+	// as-is when nothing is filtered out, or when they follow an entry of the same source that was
+	// written, since they're most likely a continuation of it.
+	skipped map[string]bool
+}
+
+func newEntryPrinter(f *filter, w *jaglogger.EntryWriter, stdout io.Writer) *entryPrinter {
+	return &entryPrinter{filter: f, writer: w, stdout: stdout, skipped: make(map[string]bool)}
+}
+
+// print writes the most recent entry read by s, if it matches the filter. When source isn't empty,
+// the entry is tagged with it: entries get a source field, and lines that can't be parsed are
+// prefixed with the source.
+func (p *entryPrinter) print(s jaglogger.Scanner, source string) {
+	e, err := s.Entry()
+	if err != nil {
+		if skipped, ok := p.skipped[source]; skipped || !ok && p.filter.active() {
+			return
+		}
+		for _, line := range strings.Split(s.Text(), "\n") {
+			if source != "" {
+				line = source + ": " + line
+			}
+			fmt.Fprintln(p.stdout, line)
+		}
+		return
+	}
+
+	p.skipped[source] = !p.filter.match(&e)
+	if p.skipped[source] {
+		return
+	}
+	if source != "" {
+		e.Fields = append(e.Fields[:len(e.Fields):len(e.Fields)], jaglogger.Field{Key: sourceKey, Value: source})
+	}
+	p.writer.Write(e)
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/williabk198/jaglogger"
+)
+
+// statsCommand reports statistics about logs.
+type statsCommand struct {
+	input  inputOptions
+	filter filterOptions
+	bucket time.Duration
+	top    int
+	json   bool
+
+	// now is replaced by tests
+	now func() time.Time
+}
+
+func runStats(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	cmd := statsCommand{now: time.Now}
+	return cmd.run(args, stdin, stdout, stderr)
+}
+
+func (c *statsCommand) run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("jaglog stats", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: jaglog stats [flags] [file ...]")
+		fmt.Fprintln(fs.Output(), "Reports the number of entries per log level over time, and the most common callers and messages.")
+		fmt.Fprintln(fs.Output(), "Reads the standard input when no files are given.")
+		fs.PrintDefaults()
+	}
+	c.input.register(fs)
+	c.filter.register(fs)
+	fs.DurationVar(&c.bucket, "bucket", time.Hour, "size of the time buckets entries are counted in")
+	fs.IntVar(&c.top, "top", 10, "number of callers and messages to report")
+	fs.BoolVar(&c.json, "json", false, "write the report as JSON")
+	if err := fs.Parse(args); err != nil {
+		return parseExitCode(err)
+	}
+
+	names := fs.Args()
+	if len(names) == 0 {
+		names = []string{"-"}
+	}
+	if c.bucket <= 0 {
+		fmt.Fprintln(stderr, "jaglog: -bucket must be positive")
+		return exitUsage
+	}
+	if err := c.input.validate(); err != nil {
+		fmt.Fprintln(stderr, "jaglog:", err)
+		return exitUsage
+	}
+	f, err := c.filter.newFilter(c.now())
+	if err != nil {
+		fmt.Fprintln(stderr, "jaglog:", err)
+		return exitUsage
+	}
+
+	stats := jaglogger.NewStats(c.bucket)
+	for _, name := range names {
+		if err := c.count(name, stdin, f, stats); err != nil {
+			return errorf(stderr, "%s", err)
+		}
+	}
+
+	report := stats.Report(c.top)
+	if c.json {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(report)
+	} else {
+		err = report.WriteText(stdout)
+	}
+	if err != nil {
+		return errorf(stderr, "%s", err)
+	}
+	return exitOK
+}
+
+// count adds the entries of the named file that match f to stats. Unparsable lines are counted
+// unless something is filtered out, since there's no telling whether they would match.
+func (c *statsCommand) count(name string, stdin io.Reader, f *filter, stats *jaglogger.Stats) error {
+	r, err := openInput(name, stdin)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	s := c.input.newScanner(r, false)
+	for s.Scan() {
+		e, err := s.Entry()
+		switch {
+		case err != nil:
+			if !f.active() {
+				stats.AddUnparsed()
+			}
+		case f.match(&e):
+			stats.Add(e)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return fmt.Errorf("reading %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const statsLogs = "[INFO]2022/07/03 22:05:03 /app/main.go:8: started\n" +
+	"[ERROR]2022/07/03 22:05:04 /app/db.go:23: query 1 failed\n" +
+	"[ERROR]2022/07/03 23:05:04 /app/db.go:23: query 2 failed\n"
+
+func TestRunStats(t *testing.T) {
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	code := run([]string{"stats", "-text-flags", "date,time,utc,longfile", "-top", "1", "-min-level", "error"},
+		strings.NewReader(statsLogs), stdout, stderr)
+	assert.Equal(t, exitOK, code, stderr.String())
+	assert.Equal(t, `Entries:  2
+First:    2022-07-03T22:05:04Z
+Last:     2022-07-03T23:05:04Z
+
+LEVEL     COUNT
+critical  0
+error     2
+warning   0
+notice    0
+info      0
+debug     0
+
+PER 1h0m0s            CRITICAL  ERROR  WARNING  NOTICE  INFO  DEBUG
+2022-07-03T22:00:00Z  0         1      0        0       0     0
+2022-07-03T23:00:00Z  0         1      0        0       0     0
+
+COUNT  FIRST                 LAST                  CALLER
+2      2022-07-03T22:05:04Z  2022-07-03T23:05:04Z  /app/db.go:23
+
+COUNT  FIRST                 LAST                  MESSAGE
+2      2022-07-03T22:05:04Z  2022-07-03T23:05:04Z  query <n> failed
+`, stdout.String())
+}
+
+func TestRunStats_JSON(t *testing.T) {
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	code := run([]string{"stats", "-json", "-bucket", "24h"}, strings.NewReader(statsLogs), stdout, stderr)
+	assert.Equal(t, exitOK, code, stderr.String())
+
+	var report struct {
+		Total       int
+		Levels      map[string]int
+		Buckets     []any
+		TopMessages []struct{ Name string } `json:"top_messages"`
+	}
+	assert.NoError(t, json.Unmarshal(stdout.Bytes(), &report))
+	assert.Equal(t, 3, report.Total)
+	assert.Equal(t, map[string]int{"info": 1, "error": 2}, report.Levels)
+	assert.Len(t, report.TopMessages, 2)
+	assert.Equal(t, "query <n> failed", report.TopMessages[0].Name)
+}
+
+func TestRunStats_InvalidBucket(t *testing.T) {
+	code := run([]string{"stats", "-bucket", "0s"}, strings.NewReader(""), new(bytes.Buffer), new(bytes.Buffer))
+	assert.Equal(t, exitUsage, code)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/williabk198/jaglogger"
+)
+
+// viewCommand pretty-prints, filters and converts logs.
+type viewCommand struct {
+	input  inputOptions
+	filter filterOptions
+	output outputOptions
+	follow bool
+
+	// now and pollInterval are replaced by tests
+	now          func() time.Time
+	pollInterval time.Duration
+	done         <-chan struct{}
+}
+
+func runView(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	cmd := viewCommand{now: time.Now, pollInterval: 250 * time.Millisecond}
+	return cmd.run(args, stdin, stdout, stderr)
+}
+
+func (c *viewCommand) run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("jaglog view", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: jaglog [view] [flags] [file ...]")
+		fmt.Fprintln(fs.Output(), "Pretty-prints, filters and converts jaglogger logs. Reads the standard input when no files are given.")
+		fs.PrintDefaults()
+	}
+	c.input.register(fs)
+	c.filter.register(fs)
+	c.output.register(fs)
+	fs.BoolVar(&c.follow, "f", false, "keep reading the file as it grows, like tail -f")
+	if err := fs.Parse(args); err != nil {
+		return parseExitCode(err)
+	}
+
+	names := fs.Args()
+	if len(names) == 0 {
+		names = []string{"-"}
+	}
+	if c.follow && (len(names) != 1 || names[0] == "-") {
+		fmt.Fprintln(stderr, "jaglog: -f needs exactly one file")
+		return exitUsage
+	}
+	if err := c.input.validate(); err != nil {
+		fmt.Fprintln(stderr, "jaglog:", err)
+		return exitUsage
+	}
+	if err := c.output.validate(); err != nil {
+		fmt.Fprintln(stderr, "jaglog:", err)
+		return exitUsage
+	}
+	f, err := c.filter.newFilter(c.now())
+	if err != nil {
+		fmt.Fprintln(stderr, "jaglog:", err)
+		return exitUsage
+	}
+
+	w := c.output.newWriter(stdout)
+	for _, name := range names {
+		if err := c.view(name, stdin, stdout, f, w); err != nil {
+			return errorf(stderr, "%s", err)
+		}
+	}
+	return exitOK
+}
+
+// view writes the entries of the named file that match f to w.
+func (c *viewCommand) view(name string, stdin io.Reader, stdout io.Writer, f *filter, w *jaglogger.EntryWriter) error {
+	var r io.ReadCloser
+	var err error
+	if c.follow {
+		r, err = openFollowReader(name, c.pollInterval, c.done)
+	} else {
+		r, err = openInput(name, stdin)
+	}
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	s := c.input.newScanner(r, c.follow)
+	p := newEntryPrinter(f, w, stdout)
+	for s.Scan() {
+		p.print(s, "")
+	}
+	if err := s.Err(); err != nil {
+		return fmt.Errorf("reading %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const textLogs = "[INFO]2022/07/03 22:05:03 /app/main.go:8: started\n" +
+	"[ERROR]2022/07/03 22:05:04 /app/db.go:23: query failed\n" +
+	"  retrying\n" +
+	"[DEBUG]2022/07/03 22:05:05 /app/db.go:30: connected\n"
+
+func TestRunView(t *testing.T) {
+	t.Setenv("FORCE_COLOR", "")
+
+	tests := []struct {
+		name     string
+		args     []string
+		stdin    string
+		want     string
+		wantCode int
+	}{
+		{
+			name:  "Text To Text",
+			args:  []string{"-format", "text", "-out-flags", "time,shortfile"},
+			stdin: textLogs,
+			want: "[INFO]22:05:03 main.go:8: started\n" +
+				"[ERROR]22:05:04 db.go:23: query failed\n  retrying\n" +
+				"[DEBUG]22:05:05 db.go:30: connected\n",
+		},
+		{
+			name:  "Level Range",
+			args:  []string{"view", "-format", "text", "-out-flags", "", "-min-level", "info", "-max-level", "warn"},
+			stdin: textLogs,
+			want:  "[INFO]started\n",
+		},
+		{
+			name:  "Time Window",
+			args:  []string{"-format", "text", "-out-flags", "", "-since", "2022-07-03 22:05:04", "-until", "2022-07-03T22:05:05"},
+			stdin: textLogs,
+			want:  "[ERROR]query failed\n  retrying\n",
+		},
+		{
+			name:  "Caller And Message",
+			args:  []string{"-format", "text", "-out-flags", "", "-caller", `db\.go:\d+`, "-msg", "^conn"},
+			stdin: textLogs,
+			want:  "[DEBUG]connected\n",
+		},
+		{
+			name:  "Text Flags",
+			args:  []string{"-text-flags", "msgprefix", "-format", "logfmt", "-out-flags", ""},
+			stdin: "[WARNING]careful\n",
+			want:  "level=warning msg=careful\n",
+		},
+		{
+			name:  "Logfmt To JSON",
+			args:  []string{"-format", "json"},
+			stdin: "\ntime=2022-07-03T22:05:03Z level=info msg=\"a test\" a=1\n",
+			want:  `{"time":"2022-07-03T22:05:03Z","level":"info","msg":"a test","a":"1"}` + "\n",
+		},
+		{
+			name:  "JSON To Logfmt",
+			args:  []string{"-format", "logfmt", "-min-level", "error"},
+			stdin: `{"level":"info","msg":"first"}` + "\n" + `{"level":"error","msg":"second","n":2}` + "\n",
+			want:  "level=error msg=second n=2\n",
+		},
+		{
+			name:  "Unparsable Lines",
+			args:  []string{"-in", "logfmt", "-format", "logfmt"},
+			stdin: "level=info msg=first\nlevel=info msg=\"broken\n",
+			want:  "level=info msg=first\nlevel=info msg=\"broken\n",
+		},
+		{
+			name:  "Console Without Color",
+			args:  []string{"-color", "never", "-out-flags", ""},
+			stdin: "level=info msg=test\n",
+			want:  "[INFO]     test\n",
+		},
+		{
+			name:  "Console With Color",
+			args:  []string{"-color", "always", "-out-flags", ""},
+			stdin: "level=info msg=test\n",
+			want:  "\x1b[32m[INFO]\x1b[0m     test\n",
+		},
+		{
+			name:     "Unknown Level",
+			args:     []string{"-min-level", "loud"},
+			wantCode: exitUsage,
+		},
+		{
+			name:     "Follow Without File",
+			args:     []string{"-f"},
+			wantCode: exitUsage,
+		},
+		{
+			name:     "Missing File",
+			args:     []string{filepath.Join(t.TempDir(), "missing.log")},
+			wantCode: exitError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+			code := run(tt.args, strings.NewReader(tt.stdin), stdout, stderr)
+			assert.Equal(t, tt.wantCode, code, stderr.String())
+			if tt.wantCode == exitOK {
+				assert.Equal(t, tt.want, stdout.String())
+			}
+		})
+	}
+}
+
+func TestRunView_Files(t *testing.T) {
+	dir := t.TempDir()
+	errLog := filepath.Join(dir, "err.log")
+	outLog := filepath.Join(dir, "out.log")
+	assert.NoError(t, os.WriteFile(errLog, []byte(`{"level":"error","msg":"first"}`+"\n"), 0o600))
+	assert.NoError(t, os.WriteFile(outLog, []byte("level=info msg=second\n"), 0o600))
+
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	code := run([]string{"-format", "logfmt", errLog, outLog}, nil, stdout, stderr)
+	assert.Equal(t, exitOK, code, stderr.String())
+	assert.Equal(t, "level=error msg=first\nlevel=info msg=second\n", stdout.String())
+}
+
+func TestViewCommand_Follow(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.log")
+	assert.NoError(t, os.WriteFile(name, []byte("level=info msg=first\n"), 0o600))
+
+	done := make(chan struct{})
+	stdout := new(syncBuffer)
+	cmd := viewCommand{now: time.Now, pollInterval: time.Millisecond, done: done}
+	exited := make(chan int)
+	go func() {
+		exited <- cmd.run([]string{"-f", "-format", "logfmt", "-msg", "keep", name}, nil, stdout, new(bytes.Buffer))
+	}()
+
+	f, err := os.OpenFile(name, os.O_APPEND|os.O_WRONLY, 0)
+	assert.NoError(t, err)
+	_, err = f.WriteString("level=info msg=\"keep this\"\nlevel=info msg=skip\n")
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	assert.Eventually(t, func() bool {
+		return stdout.String() == "level=info msg=\"keep this\"\n"
+	}, 5*time.Second, time.Millisecond)
+
+	close(done)
+	assert.Equal(t, exitOK, <-exited)
+}
+
+func TestViewCommand_FollowSmallFile(t *testing.T) {
+	// The format is detected from the first line, without waiting for more input
+	name := filepath.Join(t.TempDir(), "app.log")
+	assert.NoError(t, os.WriteFile(name, []byte("level=info msg=\"small file\"\n"), 0o600))
+
+	done := make(chan struct{})
+	stdout := new(syncBuffer)
+	cmd := viewCommand{now: time.Now, pollInterval: time.Millisecond, done: done}
+	exited := make(chan int)
+	go func() {
+		exited <- cmd.run([]string{"-f", "-format", "logfmt", name}, nil, stdout, new(bytes.Buffer))
+	}()
+
+	assert.Eventually(t, func() bool {
+		return stdout.String() == "level=info msg=\"small file\"\n"
+	}, 5*time.Second, time.Millisecond)
+
+	close(done)
+	assert.Equal(t, exitOK, <-exited)
+}
+
+func TestRunView_Pipe(t *testing.T) {
+	// Like tail -f app.log | jaglog, where stdin doesn't end
+	r, w := io.Pipe()
+	defer w.Close()
+	stdout := new(syncBuffer)
+	go run([]string{"-format", "logfmt"}, r, stdout, new(bytes.Buffer))
+
+	_, err := w.Write([]byte(`{"level":"info","msg":"piped"}` + "\n"))
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		return stdout.String() == "level=info msg=piped\n"
+	}, 5*time.Second, time.Millisecond)
+}
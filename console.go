@@ -0,0 +1,144 @@
+package jaglogger
+
+import (
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// ANSI escape codes used by FormatConsole
+const (
+	colorReset   = "\x1b[0m"
+	colorDim     = "\x1b[2m"
+	colorBoldRed = "\x1b[1;31m"
+	colorRed     = "\x1b[31m"
+	colorGreen   = "\x1b[32m"
+	colorYellow  = "\x1b[33m"
+	colorBlue    = "\x1b[34m"
+	colorMagenta = "\x1b[35m"
+	colorCyan    = "\x1b[36m"
+)
+
+var levelColors = map[LogLevel]string{
+	LogLevelCritical: colorBoldRed,
+	LogLevelError:    colorRed,
+	LogLevelWarning:  colorYellow,
+	LogLevelNotice:   colorCyan,
+	LogLevelInfo:     colorGreen,
+	LogLevelDebug:    colorMagenta,
+}
+
+const (
+	// consoleLevelWidth is the width the level column is padded to. It fits all of the default prefixes.
+	consoleLevelWidth = len("[CRITICAL]")
+	// consoleMessageWidth is the width messages followed by fields are padded to, so the fields line up.
+	consoleMessageWidth = 40
+)
+
+// appendConsole appends e to buf in the console format:
+//
+//	<time> <level> <caller>: <message> <key>=<value>...
+//
+// When color is true, the level is colored, the time and caller are dimmed and the fields are highlighted.
+func (lo *levelOutput) appendConsole(buf []byte, e *Entry, color bool) []byte {
+	if lo.hasTime() {
+		buf = appendColor(buf, color, colorDim)
+		buf = lo.appendTime(buf, e.Time)
+		buf = appendColor(buf, color, colorReset)
+		buf = append(buf, ' ')
+	}
+
+	buf = appendColor(buf, color, levelColors[e.Level])
+	buf = append(buf, lo.prefix...)
+	buf = appendColor(buf, color, colorReset)
+	buf = appendPadding(buf, consoleLevelWidth-utf8.RuneCountInString(lo.prefix)+1)
+
+	if lo.caller&CallerDisabled == 0 && e.Caller.Defined {
+		buf = appendColor(buf, color, colorDim)
+		buf = lo.appendCaller(buf, e.Caller)
+		buf = appendColor(buf, color, colorReset)
+	}
+
+	start := len(buf)
+	buf = lo.appendMessage(buf, strings.TrimSuffix(e.Message, "\n"))
+
+	if len(e.Fields) > 0 {
+		buf = appendPadding(buf, consoleMessageWidth-utf8.RuneCount(buf[start:]))
+	}
+	for _, f := range flattenFields(e.Fields) {
+		if _, ok := f.Value.(Stack); ok {
+			continue
+		}
+		buf = append(buf, ' ')
+		buf = appendColor(buf, color, levelColors[e.Level])
+		buf = lo.appendKey(buf, f.Key)
+		buf = appendColor(buf, color, colorReset)
+		buf = appendColor(buf, color, colorDim)
+		buf = append(buf, '=')
+		buf = appendColor(buf, color, colorReset)
+		if _, isErr := f.Value.(error); isErr {
+			buf = appendColor(buf, color, colorRed)
+			buf = appendValue(buf, f.Value)
+			buf = appendColor(buf, color, colorReset)
+		} else {
+			buf = appendValue(buf, f.Value)
+		}
+	}
+
+	buf = append(buf, '\n')
+
+	if !hasStackBlocks(e) {
+		return buf
+	}
+	// The stack traces are dimmed, keeping the newline that ends them after the color reset
+	buf = appendColor(buf, color, colorDim)
+	buf = appendStackBlocks(buf, e)
+	if color {
+		buf = append(buf[:len(buf)-1], colorReset+"\n"...)
+	}
+	return buf
+}
+
+func appendColor(buf []byte, color bool, code string) []byte {
+	if !color {
+		return buf
+	}
+	return append(buf, code...)
+}
+
+// appendPadding appends n spaces to buf, or a single space if n is less than 1.
+func appendPadding(buf []byte, n int) []byte {
+	if n < 1 {
+		n = 1
+	}
+	for i := 0; i < n; i++ {
+		buf = append(buf, ' ')
+	}
+	return buf
+}
+
+// useColor reports whether console entries written to w should be colored. A non-empty FORCE_COLOR
+// environment variable forces colors on, unless it's "0" or "false". Otherwise, a non-empty
+// NO_COLOR environment variable turns colors off. Without either, only terminals get colors.
+func useColor(w io.Writer) bool {
+	if force := os.Getenv("FORCE_COLOR"); force != "" {
+		return force != "0" && force != "false"
+	}
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	return isTerminal(w)
+}
+
+func isTerminal(w io.Writer) bool {
+	if rl, ok := w.(*rateLimitedOutput); ok {
+		w = rl.w
+	}
+	f, ok := w.(*os.File)
+	if !ok || f == nil {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
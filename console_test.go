@@ -0,0 +1,163 @@
+package jaglogger
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_logger_ConsoleFormat(t *testing.T) {
+	type args struct {
+		level         LogLevel
+		keysAndValues []any
+		msg           string
+	}
+
+	clock := func() time.Time {
+		return time.Date(2022, time.July, 3, 22, 5, 3, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name       string
+		args       args
+		forceColor string
+		want       string
+	}{
+		{
+			name: "No Color",
+			args: args{level: LogLevelInfo, msg: "test"},
+			want: "22:05:03 [INFO]     test\n",
+		},
+		{
+			name: "No Color With Fields",
+			args: args{level: LogLevelWarning, keysAndValues: []any{"key", "value", "err", errors.New("test error")}, msg: "test"},
+			want: "22:05:03 [WARNING]  test                                     key=value err=\"test error\"\n",
+		},
+		{
+			name:       "Forced Color",
+			args:       args{level: LogLevelInfo, msg: "test"},
+			forceColor: "1",
+			want:       "\x1b[2m22:05:03\x1b[0m \x1b[32m[INFO]\x1b[0m     test\n",
+		},
+		{
+			name:       "Forced Color With Fields",
+			args:       args{level: LogLevelError, keysAndValues: []any{"key", 1, "err", errors.New("test")}, msg: "test"},
+			forceColor: "1",
+			want: "\x1b[2m22:05:03\x1b[0m \x1b[31m[ERROR]\x1b[0m    test                                     " +
+				"\x1b[31mkey\x1b[0m\x1b[2m=\x1b[0m1 \x1b[31merr\x1b[0m\x1b[2m=\x1b[0m\x1b[31mtest\x1b[0m\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("FORCE_COLOR", tt.forceColor)
+
+			loggerOutput := new(bytes.Buffer)
+			l := NewLogger(
+				LogLevelDebug,
+				SetClockOpt(clock),
+				SetDefaultFormatOpt(FormatConsole),
+				SetDefaultFlagsOpt(log.Ltime),
+				SetDefaultErrorOutputsOpt([]io.Writer{loggerOutput}),
+				SetDefaultNonErrorOutputOpt([]io.Writer{loggerOutput}),
+			)
+
+			switch tt.args.level {
+			case LogLevelError:
+				With(l, tt.args.keysAndValues...).Error(tt.args.msg)
+			case LogLevelWarning:
+				With(l, tt.args.keysAndValues...).Warning(tt.args.msg)
+			default:
+				With(l, tt.args.keysAndValues...).Info(tt.args.msg)
+			}
+			assert.Equal(t, tt.want, loggerOutput.String())
+		})
+	}
+}
+
+func Test_useColor(t *testing.T) {
+	type args struct {
+		w io.Writer
+	}
+
+	tests := []struct {
+		name       string
+		args       args
+		forceColor string
+		noColor    string
+		want       bool
+	}{
+		{
+			name: "Not A Terminal",
+			args: args{w: new(bytes.Buffer)},
+			want: false,
+		},
+		{
+			name:       "Force Color",
+			args:       args{w: new(bytes.Buffer)},
+			forceColor: "1",
+			want:       true,
+		},
+		{
+			name:       "Force Color Disabled",
+			args:       args{w: os.Stdout},
+			forceColor: "0",
+			want:       false,
+		},
+		{
+			name:       "Force Color Overrides No Color",
+			args:       args{w: new(bytes.Buffer)},
+			forceColor: "true",
+			noColor:    "1",
+			want:       true,
+		},
+		{
+			name:    "No Color",
+			args:    args{w: os.Stdout},
+			noColor: "1",
+			want:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("FORCE_COLOR", tt.forceColor)
+			t.Setenv("NO_COLOR", tt.noColor)
+
+			got := useColor(tt.args.w)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSetColorOpt(t *testing.T) {
+	tests := []struct {
+		name       string
+		enabled    bool
+		forceColor string
+		want       bool
+	}{
+		{name: "Enabled", enabled: true, want: true},
+		{name: "Disabled Overrides Force Color", enabled: false, forceColor: "1", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("FORCE_COLOR", tt.forceColor)
+
+			loggerOutput := new(bytes.Buffer)
+			l := NewLogger(
+				LogLevelInfo,
+				SetDefaultFormatOpt(FormatConsole),
+				SetColorOpt(tt.enabled),
+				SetInfoLoggerOpt(Config{Outputs: []io.Writer{loggerOutput}, Flags: log.Lmsgprefix}),
+			)
+			l.Info("test")
+			assert.Equal(t, tt.want, strings.Contains(loggerOutput.String(), "\x1b["))
+		})
+	}
+}
@@ -0,0 +1,20 @@
+//go:build !jaglogger_nodebug
+
+package jaglogger
+
+// DebugCompiled reports whether Debug and Debugf log anything. It's false when building with the
+// jaglogger_nodebug build tag.
+const DebugCompiled = true
+
+func (l logger) Debug(v ...any) {
+	if l.sink != nil {
+		l.sink.Helper()
+	}
+	l.log(LogLevelDebug, v...)
+}
+func (l logger) Debugf(format string, v ...any) {
+	if l.sink != nil {
+		l.sink.Helper()
+	}
+	l.logf(LogLevelDebug, format, v...)
+}
@@ -0,0 +1,15 @@
+//go:build jaglogger_nodebug
+
+package jaglogger
+
+// DebugCompiled reports whether Debug and Debugf log anything. It's false when building with the
+// jaglogger_nodebug build tag.
+const DebugCompiled = false
+
+// With the jaglogger_nodebug build tag, Debug and Debugf do nothing, regardless of the minimum log
+// level. The arguments passed to them are still evaluated by the caller, and a call through the
+// Logger interface still allocates the slice that holds them, so calls should be guarded with
+// DebugCompiled, which lets the compiler remove them.
+
+func (logger) Debug(...any)          {}
+func (logger) Debugf(string, ...any) {}
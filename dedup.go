@@ -0,0 +1,99 @@
+package jaglogger
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// deduper collapses consecutive identical entries of a single log level, like syslog's "last
+// message repeated N times".
+type deduper struct {
+	mu     sync.Mutex
+	window time.Duration
+	// last is the entry that started the current run, or nil when there's none
+	last     *Entry
+	start    time.Time
+	lastTime time.Time
+	repeated int
+	timer    *time.Timer
+}
+
+func newDeduper(window time.Duration) *deduper {
+	return &deduper{window: window}
+}
+
+// keep reports whether e should be written to lo, which isn't the case when it repeats the entry
+// before it within the window. When a run of repeated entries has ended, the entry reporting it is
+// written first.
+func (d *deduper) keep(lo *levelOutput, e *Entry) bool {
+	d.mu.Lock()
+	if d.last != nil && sameEntry(d.last, e) && !e.Time.Before(d.start) && e.Time.Sub(d.start) < d.window {
+		d.repeated++
+		d.lastTime = e.Time
+		if d.timer == nil {
+			level := e.Level
+			d.timer = time.AfterFunc(d.start.Add(d.window).Sub(e.Time), func() { d.flush(lo, level) })
+		}
+		d.mu.Unlock()
+		return false
+	}
+
+	summary := d.summary(e.Level)
+	d.last = &Entry{Message: e.Message, Fields: e.Fields}
+	d.start = e.Time
+	d.mu.Unlock()
+
+	if summary != nil {
+		lo.write(summary)
+	}
+	return true
+}
+
+// flush ends the current run, and writes the entry reporting it if the entry was repeated. It's
+// called when the window that started with the run elapses, and by Close.
+func (d *deduper) flush(lo *levelOutput, level LogLevel) {
+	d.mu.Lock()
+	summary := d.summary(level)
+	d.last = nil
+	d.mu.Unlock()
+
+	if summary != nil {
+		lo.write(summary)
+	}
+}
+
+// summary returns the entry reporting how many times the entry that started the current run was
+// repeated, or nil if it wasn't. d.mu must be held.
+func (d *deduper) summary(level LogLevel) *Entry {
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+	if d.repeated == 0 {
+		return nil
+	}
+
+	e := &Entry{
+		Level:   level,
+		Time:    d.lastTime,
+		Message: fmt.Sprintf("message repeated %d times", d.repeated),
+		Fields:  []Field{Int("repeated", d.repeated)},
+	}
+	d.repeated = 0
+	return e
+}
+
+// sameEntry reports whether a and b have the same message and fields. Field values are compared
+// the way they're written, so e.g. two errors with the same message are the same.
+func sameEntry(a, b *Entry) bool {
+	if a.Message != b.Message || len(a.Fields) != len(b.Fields) {
+		return false
+	}
+	for i, f := range a.Fields {
+		if f.Key != b.Fields[i].Key || valueString(f.Value) != valueString(b.Fields[i].Value) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,155 @@
+package jaglogger
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_logger_Dedup(t *testing.T) {
+	start := time.Date(2022, time.July, 3, 22, 5, 3, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		log  func(l Logger, now *time.Time)
+		want string
+	}{
+		{
+			name: "Run Ended By Another Entry",
+			log: func(l Logger, now *time.Time) {
+				for i := 0; i < 5; i++ {
+					l.Errorf("connect failed: %v", errors.New("connection refused"))
+				}
+				l.Error("other")
+			},
+			want: "[ERROR]connect failed: connection refused\n" +
+				"[ERROR]message repeated 4 times repeated=4\n" +
+				"[ERROR]other\n",
+		},
+		{
+			name: "Different Fields",
+			log: func(l Logger, now *time.Time) {
+				With(l, "host", "a").Error("connect failed")
+				With(l, "host", "b").Error("connect failed")
+				With(l, "host", "b").Error("connect failed")
+				Close(l)
+			},
+			want: "[ERROR]connect failed host=a\n" +
+				"[ERROR]connect failed host=b\n" +
+				"[ERROR]message repeated 1 times repeated=1\n",
+		},
+		{
+			name: "Window Elapsed",
+			log: func(l Logger, now *time.Time) {
+				l.Error("connect failed")
+				l.Error("connect failed")
+				*now = now.Add(time.Minute)
+				l.Error("connect failed")
+			},
+			want: "[ERROR]connect failed\n" +
+				"[ERROR]message repeated 1 times repeated=1\n" +
+				"[ERROR]connect failed\n",
+		},
+		{
+			name: "Flushed On Close",
+			log: func(l Logger, now *time.Time) {
+				l.Error("connect failed")
+				l.Error("connect failed")
+				l.Error("connect failed")
+				Close(l)
+				l.Error("connect failed")
+			},
+			want: "[ERROR]connect failed\n" +
+				"[ERROR]message repeated 2 times repeated=2\n" +
+				"[ERROR]connect failed\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := start
+			loggerOutput := new(bytes.Buffer)
+			l := NewLogger(
+				LogLevelInfo,
+				SetClockOpt(func() time.Time { return now }),
+				SetDefaultFlagsOpt(log.Lmsgprefix),
+				SetDefaultErrorOutputsOpt([]io.Writer{loggerOutput}),
+				SetDefaultDedupOpt(time.Minute),
+			)
+
+			tt.log(l, &now)
+			assert.Equal(t, tt.want, loggerOutput.String())
+		})
+	}
+}
+
+func Test_logger_DedupSummaryTimer(t *testing.T) {
+	// The summary is written when the window that started with the run ends, not a window after the
+	// first repeat
+	start := time.Date(2022, time.July, 3, 22, 5, 3, 0, time.UTC)
+	now := start
+	entries := make(chan Entry, 10)
+	l := NewLogger(
+		LogLevelInfo,
+		SetClockOpt(func() time.Time { return now }),
+		SetDefaultNonErrorOutputOpt(nil),
+		SetDefaultDedupOpt(time.Hour),
+		SetEntryHookOpt(func(e Entry) { entries <- e }),
+	)
+
+	l.Info("same")
+	assert.Equal(t, "same", (<-entries).Message)
+	now = start.Add(time.Hour - 10*time.Millisecond)
+	l.Info("same")
+
+	select {
+	case e := <-entries:
+		assert.Equal(t, "message repeated 1 times", e.Message)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the summary wasn't written at the end of the window")
+	}
+}
+
+func Test_logger_DedupSummaryCaller(t *testing.T) {
+	loggerOutput := new(bytes.Buffer)
+	l := NewLogger(
+		LogLevelInfo,
+		SetDefaultDedupOpt(time.Hour),
+		SetInfoLoggerOpt(Config{Outputs: []io.Writer{loggerOutput}, Flags: log.Lshortfile}),
+	)
+
+	for i := 0; i < 2; i++ {
+		l.Info("same")
+	}
+	assert.NoError(t, Close(l))
+
+	assert.Regexp(t, `^\[INFO\]dedup_test\.go:\d+: same\n\[INFO\]message repeated 1 times repeated=1\n$`, loggerOutput.String())
+}
+
+func Test_logger_Close(t *testing.T) {
+	now := time.Date(2022, time.July, 3, 22, 5, 3, 0, time.UTC)
+	loggerOutput := new(bytes.Buffer)
+	l := NewLogger(
+		LogLevelInfo,
+		SetClockOpt(func() time.Time { return now }),
+		SetDefaultFlagsOpt(log.Lmsgprefix),
+		SetInfoLoggerOpt(Config{Outputs: []io.Writer{loggerOutput}, Sampling: &Sampling{Interval: time.Hour, First: 1}}),
+		SetErrorLoggerOpt(Config{Outputs: []io.Writer{RateLimitOutput(loggerOutput, RateLimit{Rate: 0.001})}}),
+	)
+
+	l.Info("sampled")
+	l.Info("sampled")
+	l.Error("limited")
+	l.Error("limited")
+	assert.NoError(t, Close(l))
+
+	want := "[INFO]sampled\n" +
+		"[ERROR]limited\n" +
+		"[INFO]sampling dropped 1 entries dropped=1\n" +
+		"[ERROR]rate limit dropped 1 entries dropped=1\n"
+	assert.Equal(t, want, loggerOutput.String())
+}
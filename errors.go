@@ -0,0 +1,110 @@
+package jaglogger
+
+import (
+	"errors"
+	"reflect"
+)
+
+// The keys of the fields added for an error logged with one of the level methods. The fields an error
+// contributes through ObjectMarshaler get keys prefixed with errorKey and a dot as well.
+const (
+	errorChainKey = errorKey + ".chain"
+	errorStackKey = errorKey + ".stack"
+)
+
+// maxErrorChain limits how many errors of a chain are reported, in case it's cyclic.
+const maxErrorChain = 64
+
+// errorFromArgs returns the first of the arguments of a logging call that's an error.
+func errorFromArgs(v []any) error {
+	for _, arg := range v {
+		if err, ok := arg.(error); ok && err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// errorFields returns the fields that describe err:
+//
+//   - error.chain holds the messages of err and the errors it wraps, when there are any. Both
+//     errors.Unwrap and the Unwrap() []error method, which the errors created by errors.Join have
+//     since Go 1.20, are followed, depth-first.
+//   - error.stack holds the stack trace carried by the innermost error that has one.
+//   - Errors in the chain that implement ObjectMarshaler add their fields, with their keys prefixed
+//     by "error.".
+func errorFields(err error) []Field {
+	chain := errorChain(err)
+
+	var fields []Field
+	if len(chain) > 1 {
+		messages := make([]string, len(chain))
+		for i, e := range chain {
+			messages[i] = e.Error()
+		}
+		fields = append(fields, Field{Key: errorChainKey, Value: messages})
+	}
+
+	for i := len(chain) - 1; i >= 0; i-- {
+		if stack := errorStack(chain[i]); len(stack) > 0 {
+			fields = append(fields, Field{Key: errorStackKey, Value: stack})
+			break
+		}
+	}
+
+	for _, e := range chain {
+		if obj, ok := e.(ObjectMarshaler); ok {
+			for _, f := range obj.LogFields() {
+				fields = append(fields, Field{Key: errorKey + "." + f.Key, Value: f.Value})
+			}
+		}
+	}
+	return fields
+}
+
+// errorChain returns err followed by the errors it wraps, depth-first.
+func errorChain(err error) []error {
+	var chain []error
+	var walk func(error)
+	walk = func(err error) {
+		for err != nil && len(chain) < maxErrorChain {
+			chain = append(chain, err)
+			if multi, ok := err.(interface{ Unwrap() []error }); ok {
+				for _, e := range multi.Unwrap() {
+					walk(e)
+				}
+				return
+			}
+			err = errors.Unwrap(err)
+		}
+	}
+	walk(err)
+	return chain
+}
+
+// errorStack returns the stack trace carried by err, if it has one. Errors can carry one by
+// implementing a Callers() []uintptr method that returns the program counters recorded with
+// runtime.Callers, or a StackTrace method that returns a slice of them, like the errors of
+// github.com/pkg/errors. Only the stack trace of err itself is returned, not of the errors it wraps.
+func errorStack(err error) Stack {
+	if c, ok := err.(interface{ Callers() []uintptr }); ok {
+		return stackFromPCs(c.Callers())
+	}
+
+	// The StackTrace methods of the various error packages return their own slice types, so the
+	// program counters have to be taken out with reflection
+	method := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !method.IsValid() {
+		return nil
+	}
+	t := method.Type()
+	if t.NumIn() != 0 || t.NumOut() != 1 || t.Out(0).Kind() != reflect.Slice || t.Out(0).Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+	trace := method.Call(nil)[0]
+	pcs := make([]uintptr, trace.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(trace.Index(i).Uint())
+	}
+	return stackFromPCs(pcs)
+}
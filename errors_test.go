@@ -0,0 +1,140 @@
+package jaglogger
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stackError carries the stack of where it was created, like the errors of github.com/pkg/errors.
+type stackError struct {
+	msg string
+	pcs []uintptr
+}
+
+func newStackError(msg string) error {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	return &stackError{msg: msg, pcs: pcs[:n]}
+}
+
+func (e *stackError) Error() string { return e.msg }
+
+// pkgErrorsFrame mirrors the Frame type of github.com/pkg/errors.
+type pkgErrorsFrame uintptr
+
+type pkgErrorsStackTrace []pkgErrorsFrame
+
+func (e *stackError) StackTrace() pkgErrorsStackTrace {
+	trace := make(pkgErrorsStackTrace, len(e.pcs))
+	for i, pc := range e.pcs {
+		trace[i] = pkgErrorsFrame(pc)
+	}
+	return trace
+}
+
+// statusError contributes its status code as a field.
+type statusError struct {
+	status int
+}
+
+func (e statusError) Error() string { return fmt.Sprintf("status %d", e.status) }
+
+func (e statusError) LogFields() []Field { return []Field{Int("status", e.status)} }
+
+// joinError wraps multiple errors like the ones returned by errors.Join, which isn't available in Go 1.18.
+type joinError []error
+
+func (e joinError) Error() string { return "joined" }
+
+func (e joinError) Unwrap() []error { return e }
+
+func Test_errorChain(t *testing.T) {
+	base := errors.New("base")
+	other := errors.New("other")
+	wrapped := fmt.Errorf("wrapped: %w", base)
+	joined := joinError{wrapped, other}
+
+	tests := []struct {
+		name string
+		err  error
+		want []error
+	}{
+		{name: "Single", err: base, want: []error{base}},
+		{name: "Wrapped", err: wrapped, want: []error{wrapped, base}},
+		{name: "Multiple", err: joined, want: []error{joined, wrapped, base, other}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, errorChain(tt.err))
+		})
+	}
+}
+
+func Test_errorStack(t *testing.T) {
+	err := newStackError("test")
+	stack := errorStack(err)
+	if assert.NotEmpty(t, stack) {
+		assert.Equal(t, "github.com/williabk198/jaglogger.Test_errorStack", stack[0].Function)
+		assert.True(t, strings.HasSuffix(stack[0].File, "errors_test.go"))
+	}
+	assert.Empty(t, errorStack(errors.New("test")))
+}
+
+func Test_logger_ErrorFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		logFn  func(Logger, error)
+		err    error
+		want   string
+	}{
+		{
+			name:   "Plain Error",
+			format: FormatLogfmt,
+			logFn:  func(l Logger, err error) { l.Error(err) },
+			err:    errors.New("failed"),
+			want:   "level=error msg=failed\n",
+		},
+		{
+			name:   "Chain",
+			format: FormatLogfmt,
+			logFn:  func(l Logger, err error) { l.Error(err) },
+			err:    fmt.Errorf("read config: %w", statusError{status: 404}),
+			want:   `level=error msg="read config: status 404" error.chain="[\"read config: status 404\",\"status 404\"]" error.status=404` + "\n",
+		},
+		{
+			name:   "Chain JSON",
+			format: FormatJSON,
+			logFn:  func(l Logger, err error) { l.Errorf("request failed: %v", err) },
+			err:    fmt.Errorf("read config: %w", statusError{status: 404}),
+			want:   `{"level":"error","msg":"request failed: read config: status 404","error.chain":["read config: status 404","status 404"],"error.status":404}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loggerOutput := new(bytes.Buffer)
+			l := NewLogger(LogLevelInfo, SetErrorLoggerOpt(Config{Outputs: []io.Writer{loggerOutput}, Flags: log.Lmsgprefix, Format: tt.format}))
+
+			tt.logFn(l, tt.err)
+			assert.Equal(t, tt.want, loggerOutput.String())
+		})
+	}
+}
+
+func Test_logger_ErrorStack(t *testing.T) {
+	loggerOutput := new(bytes.Buffer)
+	l := NewLogger(LogLevelInfo, SetErrorLoggerOpt(Config{Outputs: []io.Writer{loggerOutput}, Flags: log.Lmsgprefix, Format: FormatJSON}))
+
+	With(l, "a", 1).Error(fmt.Errorf("wrapped: %w", newStackError("failed")))
+	got := loggerOutput.String()
+	assert.Regexp(t, `^\{"level":"error","msg":"wrapped: failed","a":1,"error\.chain":\["wrapped: failed","failed"\],`+
+		`"error\.stack":\[\{"func":"github\.com/williabk198/jaglogger\.Test_logger_ErrorStack","file":"[^"]*errors_test\.go","line":\d+\}`, got)
+}
@@ -0,0 +1,286 @@
+package jaglogger
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Field is a key/value pair attached to a log entry. Fields are best created with the typed
+// constructors, like String and Int, whose values are encoded without reflection.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// badKey is the key given to a value that's missing its key, like the last element of an odd-length
+// key/value list.
+const badKey = "!BADKEY"
+
+// errorKey is the key used by Err.
+const errorKey = "error"
+
+// String returns a Field with a string value.
+func String(key, value string) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int returns a Field with an int value.
+func Int(key string, value int) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Int64 returns a Field with an int64 value.
+func Int64(key string, value int64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Float64 returns a Field with a float64 value.
+func Float64(key string, value float64) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Bool returns a Field with a bool value.
+func Bool(key string, value bool) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Duration returns a Field with a time.Duration value, which is written like "1.5s".
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Time returns a Field with a time.Time value, which is written in RFC 3339 with nanoseconds.
+func Time(key string, value time.Time) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Err returns a Field with the key "error" and err as its value, which is written as err.Error().
+func Err(err error) Field {
+	return Field{Key: errorKey, Value: err}
+}
+
+// Any returns a Field with a value of any type. Values of the types that have their own constructor
+// are encoded the same way as with that constructor, and anything else is encoded with reflection.
+func Any[T any](key string, value T) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Stringer returns a Field whose value is written as value.String(). String is only called when
+// the entry is written.
+func Stringer(key string, value fmt.Stringer) Field {
+	return Field{Key: key, Value: value}
+}
+
+// ObjectMarshaler is implemented by types that describe themselves as a list of fields, so they can be
+// logged without reflection.
+type ObjectMarshaler interface {
+	LogFields() []Field
+}
+
+// objectValue returns v as an ObjectMarshaler, unless it isn't one or it's an error. Errors are
+// written as their message, and only contribute their fields when logged with the level methods.
+func objectValue(v any) (ObjectMarshaler, bool) {
+	if _, isErr := v.(error); isErr {
+		return nil, false
+	}
+	obj, ok := v.(ObjectMarshaler)
+	return obj, ok
+}
+
+// Object returns a Field whose value is the fields of value. In the JSON format it's written as a
+// nested object, and in the other formats each of its fields is written with a key prefixed by the
+// key of the object, e.g. "user.id=1 user.name=gopher". LogFields is only called when the entry is
+// written.
+func Object(key string, value ObjectMarshaler) Field {
+	return Field{Key: key, Value: value}
+}
+
+// With returns a Logger that attaches the given key/value pairs to every entry logged by l, e.g.
+// With(logger, "user", userID, "attempt", 3). Fields created with the typed constructors can be
+// used in place of a pair, e.g. With(logger, jaglogger.String("user", userID), "attempt", 3).
+// Keys that aren't strings are converted to one, and a key without a value becomes the value
+// of a "!BADKEY" field. It calls the With(...any) Logger method of l, and returns l unchanged if
+// there isn't one, which drops the fields.
+func With(l Logger, keysAndValues ...any) Logger {
+	if w, ok := l.(interface{ With(...any) Logger }); ok {
+		return w.With(keysAndValues...)
+	}
+	return l
+}
+
+func (l logger) With(keysAndValues ...any) Logger {
+	fields := make([]Field, len(l.fields), len(l.fields)+len(keysAndValues)/2+1)
+	copy(fields, l.fields)
+	l.fields = append(fields, fieldsFromKeyValues(keysAndValues)...)
+	return l
+}
+
+// fieldsFromKeyValues pairs up the keys and values, taking Fields as they are.
+func fieldsFromKeyValues(keysAndValues []any) []Field {
+	fields := make([]Field, 0, len(keysAndValues)/2+1)
+	for i := 0; i < len(keysAndValues); i++ {
+		if f, ok := keysAndValues[i].(Field); ok {
+			fields = append(fields, f)
+			continue
+		}
+		if i+1 == len(keysAndValues) {
+			fields = append(fields, Field{Key: badKey, Value: keysAndValues[i]})
+			break
+		}
+
+		key, ok := keysAndValues[i].(string)
+		if !ok {
+			key = fmt.Sprint(keysAndValues[i])
+		}
+		fields = append(fields, Field{Key: key, Value: keysAndValues[i+1]})
+		i++
+	}
+	return fields
+}
+
+// flattenFields replaces the fields whose value is an ObjectMarshaler with its fields, prefixing
+// their keys with the key of the object and a dot. It returns fields itself when there are none.
+func flattenFields(fields []Field) []Field {
+	hasObject := false
+	for _, f := range fields {
+		if _, ok := objectValue(f.Value); ok {
+			hasObject = true
+			break
+		}
+	}
+	if !hasObject {
+		return fields
+	}
+
+	flat := make([]Field, 0, len(fields))
+	for _, f := range fields {
+		obj, ok := objectValue(f.Value)
+		if !ok {
+			flat = append(flat, f)
+			continue
+		}
+		for _, sub := range flattenFields(obj.LogFields()) {
+			flat = append(flat, Field{Key: f.Key + "." + sub.Key, Value: sub.Value})
+		}
+	}
+	return flat
+}
+
+// appendFields appends each field to buf as " key=value". Fields whose value is a Stack are left
+// out, since they're written as blocks after the entry.
+func (lo *levelOutput) appendFields(buf []byte, fields []Field) []byte {
+	for _, f := range flattenFields(fields) {
+		if _, ok := f.Value.(Stack); ok {
+			continue
+		}
+		buf = append(buf, ' ')
+		buf = lo.appendKey(buf, f.Key)
+		buf = append(buf, '=')
+		buf = appendValue(buf, f.Value)
+	}
+	return buf
+}
+
+// appendValue appends v to buf, quoting it if it's empty or contains spaces, quotes, equal signs
+// or characters that aren't printable.
+func appendValue(buf []byte, v any) []byte {
+	if b, ok := appendNumber(buf, v); ok {
+		return b
+	}
+	s := valueString(v)
+	if needsQuoting(s) {
+		return strconv.AppendQuote(buf, s)
+	}
+	return append(buf, s...)
+}
+
+// appendNumber appends v to buf if it's a bool, an integer or a finite float, which are written
+// the same way by all formats.
+func appendNumber(buf []byte, v any) ([]byte, bool) {
+	switch v := v.(type) {
+	case bool:
+		return strconv.AppendBool(buf, v), true
+	case int:
+		return strconv.AppendInt(buf, int64(v), 10), true
+	case int8:
+		return strconv.AppendInt(buf, int64(v), 10), true
+	case int16:
+		return strconv.AppendInt(buf, int64(v), 10), true
+	case int32:
+		return strconv.AppendInt(buf, int64(v), 10), true
+	case int64:
+		return strconv.AppendInt(buf, v, 10), true
+	case uint:
+		return strconv.AppendUint(buf, uint64(v), 10), true
+	case uint8:
+		return strconv.AppendUint(buf, uint64(v), 10), true
+	case uint16:
+		return strconv.AppendUint(buf, uint64(v), 10), true
+	case uint32:
+		return strconv.AppendUint(buf, uint64(v), 10), true
+	case uint64:
+		return strconv.AppendUint(buf, v, 10), true
+	case float32:
+		if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+			return buf, false
+		}
+		return strconv.AppendFloat(buf, float64(v), 'g', -1, 32), true
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return buf, false
+		}
+		return strconv.AppendFloat(buf, v, 'g', -1, 64), true
+	default:
+		return buf, false
+	}
+}
+
+// valueString returns v as a string. Only values of types without a constructor are formatted with
+// reflection.
+func valueString(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case time.Duration:
+		return v.String()
+	case []string:
+		return string(appendStrings(nil, v))
+	case error, fmt.Stringer:
+		// fmt calls the Error and String methods without reflection, and handles nil receivers
+		return fmt.Sprint(v)
+	}
+	if b, ok := appendNumber(nil, v); ok {
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
+
+// appendStrings appends s to buf as a JSON array.
+func appendStrings(buf []byte, s []string) []byte {
+	buf = append(buf, '[')
+	for i, str := range s {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = appendQuoted(buf, str)
+	}
+	return append(buf, ']')
+}
+
+func needsQuoting(s string) bool {
+	if s == "" {
+		return true
+	}
+	for _, r := range s {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+package jaglogger
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"log"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_logger_With(t *testing.T) {
+	loggerOutput := new(bytes.Buffer)
+	l := NewLogger(LogLevelInfo, SetInfoLoggerOpt(Config{Outputs: []io.Writer{loggerOutput}, Flags: log.Lmsgprefix}))
+
+	parent := With(l, "a", 1)
+	child1 := With(parent, "b", "two words")
+	child2 := With(parent, "c", nil)
+
+	parent.Info("test")
+	child1.Info("test")
+	child2.Info("test")
+	l.Info("test")
+
+	want := "[INFO]test a=1\n" +
+		"[INFO]test a=1 b=\"two words\"\n" +
+		"[INFO]test a=1 c=<nil>\n" +
+		"[INFO]test\n"
+	assert.Equal(t, want, loggerOutput.String())
+}
+
+func Test_fieldsFromKeyValues(t *testing.T) {
+	tests := []struct {
+		name          string
+		keysAndValues []any
+		want          []Field
+	}{
+		{
+			name:          "Pairs",
+			keysAndValues: []any{"a", 1, "b", "2"},
+			want:          []Field{{Key: "a", Value: 1}, {Key: "b", Value: "2"}},
+		},
+		{
+			name:          "Non-String Key",
+			keysAndValues: []any{1, 2},
+			want:          []Field{{Key: "1", Value: 2}},
+		},
+		{
+			name:          "Missing Value",
+			keysAndValues: []any{"a", 1, "b"},
+			want:          []Field{{Key: "a", Value: 1}, {Key: "!BADKEY", Value: "b"}},
+		},
+		{
+			name:          "Typed Fields",
+			keysAndValues: []any{Int("a", 1), "b", 2, String("c", "3")},
+			want:          []Field{{Key: "a", Value: 1}, {Key: "b", Value: 2}, {Key: "c", Value: "3"}},
+		},
+		{
+			name:          "Typed Field Then Missing Value",
+			keysAndValues: []any{Bool("a", true), "b"},
+			want:          []Field{{Key: "a", Value: true}, {Key: "!BADKEY", Value: "b"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fieldsFromKeyValues(tt.keysAndValues)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_appendValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{name: "Plain String", value: "test", want: "test"},
+		{name: "Empty String", value: "", want: `""`},
+		{name: "Spaces", value: "test test", want: `"test test"`},
+		{name: "Equals", value: "a=b", want: `"a=b"`},
+		{name: "Quotes", value: `"test"`, want: `"\"test\""`},
+		{name: "Newline", value: "test\ntest", want: `"test\ntest"`},
+		{name: "Number", value: 1.5, want: "1.5"},
+		{name: "Error", value: errors.New("test"), want: "test"},
+		{name: "Nil Error", value: (*fs.PathError)(nil), want: "<nil>"},
+		{name: "Duration", value: 1500 * time.Millisecond, want: "1.5s"},
+		{name: "Time", value: time.Date(2022, time.July, 3, 22, 5, 3, 500, time.UTC), want: "2022-07-03T22:05:03.0000005Z"},
+		{name: "Negative Int", value: int64(-3), want: "-3"},
+		{name: "NaN", value: math.NaN(), want: "NaN"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(appendValue(nil, tt.value))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+type testUser struct {
+	id   int
+	name string
+}
+
+func (u testUser) LogFields() []Field {
+	return []Field{Int("id", u.id), String("name", u.name)}
+}
+
+func Test_logger_TypedFields(t *testing.T) {
+	clock := func() time.Time {
+		return time.Date(2022, time.July, 3, 22, 5, 3, 0, time.UTC)
+	}
+	fields := []any{
+		String("s", "a b"),
+		Int("i", 1),
+		Int64("i64", -2),
+		Float64("f", 0.5),
+		Bool("b", false),
+		Duration("d", time.Minute),
+		Time("t", clock()),
+		Err(errors.New("failed")),
+		Any("any", []int{1}),
+		Stringer("stringer", LogLevelInfo),
+		Object("user", testUser{id: 7, name: "gopher"}),
+		"odd",
+	}
+
+	tests := []struct {
+		name   string
+		format Format
+		want   string
+	}{
+		{
+			name:   "Text",
+			format: FormatText,
+			want: `[INFO]test s="a b" i=1 i64=-2 f=0.5 b=false d=1m0s t=2022-07-03T22:05:03Z error=failed any=[1] ` +
+				`stringer=[INFO] user.id=7 user.name=gopher !BADKEY=odd` + "\n",
+		},
+		{
+			name:   "Logfmt",
+			format: FormatLogfmt,
+			want: `level=info msg=test s="a b" i=1 i64=-2 f=0.5 b=false d=1m0s t=2022-07-03T22:05:03Z error=failed any=[1] ` +
+				`stringer=[INFO] user.id=7 user.name=gopher !BADKEY=odd` + "\n",
+		},
+		{
+			name:   "JSON",
+			format: FormatJSON,
+			want: `{"level":"info","msg":"test","s":"a b","i":1,"i64":-2,"f":0.5,"b":false,"d":"1m0s","t":"2022-07-03T22:05:03Z",` +
+				`"error":"failed","any":[1],"stringer":"[INFO]","user":{"id":7,"name":"gopher"},"!BADKEY":"odd"}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loggerOutput := new(bytes.Buffer)
+			l := NewLogger(LogLevelInfo, SetInfoLoggerOpt(Config{Outputs: []io.Writer{loggerOutput}, Flags: log.Lmsgprefix, Format: tt.format}))
+
+			With(l, fields...).Info("test")
+			assert.Equal(t, tt.want, loggerOutput.String())
+		})
+	}
+}
+
+func Benchmark_logger_TypedFields(b *testing.B) {
+	l := NewLogger(LogLevelInfo, SetInfoLoggerOpt(Config{Outputs: []io.Writer{io.Discard}, Format: FormatJSON, Caller: CallerDisabled}))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		With(l, String("user", "gopher"), Int("attempt", i), Duration("elapsed", time.Second)).Info("test")
+	}
+}
@@ -0,0 +1,30 @@
+package jaglogger
+
+// Format determines how log entries are written. The zero value means that the default format is
+// used, which is the one set by SetDefaultFormatOpt, or FormatText.
+type Format int
+
+const (
+	// FormatText writes entries the same way the log package does, followed by their fields.
+	// This is the default.
+	FormatText Format = iota + 1
+	// FormatConsole writes entries in a colorized, column aligned format meant for people to read
+	// in a terminal. Colors are only used for outputs that are terminals, unless the FORCE_COLOR
+	// environment variable is set. Setting the NO_COLOR environment variable disables colors.
+	FormatConsole
+	// FormatLogfmt writes entries in the logfmt format, with the time, level, caller, function and
+	// message under the time, level, caller, func and msg keys respectively:
+	//
+	//	time=2022-07-03T22:05:03-05:00 level=info caller=main.go:8 msg="a message" key=value
+	//
+	// Values are quoted when they're empty or contain spaces, quotes, equal signs or control
+	// characters, using the same escape sequences as JSON. ParseLogfmt and LogfmtScanner read
+	// entries written in this format.
+	FormatLogfmt
+	// FormatJSON writes each entry as a JSON object on its own line, using the same keys as FormatLogfmt:
+	//
+	//	{"time":"2022-07-03T22:05:03-05:00","level":"info","caller":"main.go:8","msg":"a message","key":"value"}
+	//
+	// ParseJSON and NewJSONScanner read entries written in this format.
+	FormatJSON
+)
@@ -5,8 +5,14 @@ import (
 	"io"
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
+// Logger is implemented by the loggers returned by NewLogger and Nop. Functions such as With, V,
+// StdLogger and Writer take a Logger as their first argument instead of being part of it, so that
+// other implementations only need the logging methods.
 type Logger interface {
 	Critical(...any)
 	Criticalf(string, ...any)
@@ -52,67 +58,281 @@ func (ll LogLevel) String() string {
 	}
 }
 
+// name returns the lowercase name of the log level, as used by the structured formats.
+func (ll LogLevel) name() string {
+	switch ll {
+	case LogLevelCritical:
+		return "critical"
+	case LogLevelError:
+		return "error"
+	case LogLevelWarning:
+		return "warning"
+	case LogLevelNotice:
+		return "notice"
+	case LogLevelInfo:
+		return "info"
+	case LogLevelDebug:
+		return "debug"
+	default:
+		return strconv.Itoa(int(ll))
+	}
+}
+
+// ParseLogLevel returns the LogLevel with the given name. The name is case insensitive, may be
+// surrounded by square brackets, and may be a common abbreviation like "warn" or "err".
+func ParseLogLevel(name string) (LogLevel, error) {
+	trimmed := strings.TrimSuffix(strings.TrimPrefix(name, "["), "]")
+	for _, lk := range levelKeywords {
+		if strings.EqualFold(trimmed, lk.keyword) {
+			return lk.level, nil
+		}
+	}
+	return 0, fmt.Errorf("jaglogger: unknown log level %q", name)
+}
+
 type logger struct {
-	outputs map[LogLevel]*log.Logger
+	outputs    map[LogLevel]*levelOutput
+	callerSkip int
+	helpers    *helperFuncs
+	clock      func() time.Time
+	fields     []Field
+	cadence    *cadence
+	cadences   *cadences
+	verbosity  *Verbosity
+	// sink is the Sink set with SetSinkOpt, or nil
+	sink Sink
 }
 
 func (l logger) Critical(v ...any) {
+	if l.sink != nil {
+		l.sink.Helper()
+	}
 	l.log(LogLevelCritical, v...)
 }
 func (l logger) Criticalf(format string, v ...any) {
+	if l.sink != nil {
+		l.sink.Helper()
+	}
 	l.logf(LogLevelCritical, format, v...)
 }
 
 func (l logger) Error(v ...any) {
+	if l.sink != nil {
+		l.sink.Helper()
+	}
 	l.log(LogLevelError, v...)
 }
 func (l logger) Errorf(format string, v ...any) {
+	if l.sink != nil {
+		l.sink.Helper()
+	}
 	l.logf(LogLevelError, format, v...)
 }
 
 func (l logger) Warning(v ...any) {
+	if l.sink != nil {
+		l.sink.Helper()
+	}
 	l.log(LogLevelWarning, v...)
 }
 func (l logger) Warningf(format string, v ...any) {
+	if l.sink != nil {
+		l.sink.Helper()
+	}
 	l.logf(LogLevelWarning, format, v...)
 }
 
 func (l logger) Notice(v ...any) {
+	if l.sink != nil {
+		l.sink.Helper()
+	}
 	l.log(LogLevelNotice, v...)
 }
 func (l logger) Noticef(format string, v ...any) {
+	if l.sink != nil {
+		l.sink.Helper()
+	}
 	l.logf(LogLevelNotice, format, v...)
 }
 
 func (l logger) Info(v ...any) {
+	if l.sink != nil {
+		l.sink.Helper()
+	}
 	l.log(LogLevelInfo, v...)
 }
 func (l logger) Infof(format string, v ...any) {
+	if l.sink != nil {
+		l.sink.Helper()
+	}
 	l.logf(LogLevelInfo, format, v...)
 }
 
-func (l logger) Debug(v ...any) {
-	l.log(LogLevelDebug, v...)
+func (l logger) log(level LogLevel, v ...any) {
+	if l.sink != nil {
+		l.sink.Helper()
+	}
+	v = redactArgs(v)
+	l.output(3, level, fmt.Sprint(v...), "", errorFromArgs(v))
 }
-func (l logger) Debugf(format string, v ...any) {
-	l.logf(LogLevelDebug, format, v...)
+
+func (l logger) logf(level LogLevel, format string, v ...any) {
+	if l.sink != nil {
+		l.sink.Helper()
+	}
+	v = redactArgs(v)
+	l.output(3, level, fmt.Sprintf(format, v...), format, errorFromArgs(v))
 }
 
-func (l logger) log(level LogLevel, v ...any) {
-	if logOutput, ok := l.outputs[level]; ok {
-		logOutput.Output(3, fmt.Sprint(v...))
+// output writes msg to the logger of the given level. Like log.Output, calldepth is the count
+// of stack frames to skip when reporting the caller, with 1 being the caller of output. key is the
+// format string msg was made from, which Sampling uses to group entries, or "" if there isn't one.
+// When err isn't nil, the fields that describe it are added to the entry.
+func (l logger) output(calldepth int, level LogLevel, msg, key string, err error) {
+	lo, ok := l.outputs[level]
+	if !ok || !lo.enabled() {
+		return
+	}
+	if lo.sink != nil {
+		lo.sink.Helper()
+	}
+
+	e := Entry{Level: level, Time: l.now(), Message: msg}
+	if l.cadence != nil && !l.cadences.allow(*l.cadence, lookupCaller(calldepth+l.callerSkip, l.helpers), e.Time) {
+		return
+	}
+	if lo.sampler != nil && !lo.sampler.keep(lo, &e, key) {
+		return
+	}
+	if lo.limiter != nil && !lo.allow(&e) {
+		return
+	}
+	e.Fields = l.entryFields(err)
+	if lo.deduper != nil && !lo.deduper.keep(lo, &e) {
+		return
+	}
+	if lo.caller&CallerDisabled == 0 {
+		e.Caller = lookupCaller(calldepth+l.callerSkip, l.helpers)
+	}
+	if lo.stackTrace {
+		e.Stack = captureStack(calldepth+l.callerSkip, l.helpers)
+	}
+	lo.write(&e)
+}
+
+// outputter is implemented by the loggers that the writers returned by StdLogger and Writer log to.
+type outputter interface {
+	output(calldepth int, level LogLevel, msg, key string, err error)
+}
 
+// outputterOf returns l as an outputter. Loggers from outside of this package are given each
+// message through the method of its log level, so they report the caller themselves.
+func outputterOf(l Logger) outputter {
+	if o, ok := l.(outputter); ok {
+		return o
 	}
+	return levelMethods{l: l}
 }
 
-func (l logger) logf(level LogLevel, format string, v ...any) {
-	if logOutput, ok := l.outputs[level]; ok {
-		logOutput.Output(3, fmt.Sprintf(format, v...))
+// levelMethods is the outputter of a Logger from outside of this package.
+type levelMethods struct {
+	l Logger
+}
+
+func (m levelMethods) output(_ int, level LogLevel, msg, _ string, _ error) {
+	switch level {
+	case LogLevelCritical:
+		m.l.Critical(msg)
+	case LogLevelError:
+		m.l.Error(msg)
+	case LogLevelWarning:
+		m.l.Warning(msg)
+	case LogLevelNotice:
+		m.l.Notice(msg)
+	case LogLevelDebug:
+		m.l.Debug(msg)
+	default:
+		m.l.Info(msg)
+	}
+}
+
+// now returns the current time according to the clock of the logger.
+func (l logger) now() time.Time {
+	if l.clock != nil {
+		return l.clock()
 	}
+	return time.Now()
+}
+
+// entryFields returns the fields of an entry, which are those of the logger followed by the fields
+// that describe err when it isn't nil.
+func (l logger) entryFields(err error) []Field {
+	if err == nil {
+		return l.fields
+	}
+	errFields := errorFields(err)
+	if len(errFields) == 0 {
+		return l.fields
+	}
+	return append(l.fields[:len(l.fields):len(l.fields)], errFields...)
+}
+
+// AddCallerSkip returns a Logger that skips n additional stack frames when reporting the caller.
+// This is useful for libraries that wrap a Logger, so the caller of the wrapper gets reported. It
+// calls the AddCallerSkip(int) Logger method of l, and returns l unchanged if there isn't one.
+func AddCallerSkip(l Logger, n int) Logger {
+	if s, ok := l.(interface{ AddCallerSkip(int) Logger }); ok {
+		return s.AddCallerSkip(n)
+	}
+	return l
+}
+
+func (l logger) AddCallerSkip(n int) Logger {
+	l.callerSkip += n
+	return l
+}
+
+// Helper marks the calling function as a logging helper function of l, similar to
+// testing.T.Helper. When reporting the caller, helper functions are skipped. This applies to l and
+// all Loggers derived from it. Loggers from outside of this package get their Helper() method
+// called, if they have one.
+func Helper(l Logger) {
+	switch l := l.(type) {
+	case logger:
+		l.helpers.add(1)
+	case interface{ Helper() }:
+		l.Helper()
+	}
+}
+
+// Close writes the entries that report what was left out by Sampling, RateLimit and Dedup, without
+// waiting for the end of their intervals, and flushes the outputs that have a Flush or Sync method.
+// The outputs aren't closed, and the logger can still be used afterwards. It calls the Close() error
+// method of l, and returns nil if there isn't one.
+func Close(l Logger) error {
+	if c, ok := l.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+func (l logger) Close() error {
+	for level := LogLevelDebug; level <= LogLevelCritical; level++ {
+		if lo, ok := l.outputs[level]; ok {
+			lo.flushPending(level)
+		}
+	}
+	l.flush()
+	return nil
 }
 
 func NewLogger(minLevel LogLevel, opts ...Option) Logger {
+	outputs, loggerSettings := newLevelOutputs(minLevel, opts)
+	return logger{outputs: outputs, helpers: &helperFuncs{}, clock: loggerSettings.Clock, cadences: &cadences{}, verbosity: loggerSettings.Verbosity, sink: loggerSettings.Sink}
+}
 
+// newLevelOutputs applies opts to the default settings, and creates the output of each log level from them.
+func newLevelOutputs(minLevel LogLevel, opts []Option) (map[LogLevel]*levelOutput, settings) {
 	//initialize settings with default values
 	loggerSettings := settings{
 		LogLevelConfigs: map[LogLevel]Config{
@@ -141,6 +361,21 @@ func NewLogger(minLevel LogLevel, opts ...Option) Logger {
 		if conf.Prefix == "" {
 			conf.Prefix = logLevel.String()
 		}
+		if conf.Caller == 0 {
+			conf.Caller = loggerSettings.DefaultCaller
+		}
+		if conf.Caller == 0 {
+			conf.Caller = callerFormatFromFlags(conf.Flags)
+		}
+		if conf.Format == 0 {
+			conf.Format = loggerSettings.DefaultFormat
+		}
+		if conf.Sampling == nil {
+			conf.Sampling = &loggerSettings.DefaultSampling
+		}
+		if conf.Dedup <= 0 {
+			conf.Dedup = loggerSettings.DefaultDedup
+		}
 		if len(conf.Outputs) == 0 && logLevel >= minLevel {
 			if logLevel >= LogLevelWarning {
 				conf.Outputs = loggerSettings.DefaultErrOutputs
@@ -151,38 +386,48 @@ func NewLogger(minLevel LogLevel, opts ...Option) Logger {
 		loggerSettings.LogLevelConfigs[logLevel] = conf
 	}
 
-	return logger{
-		outputs: map[LogLevel]*log.Logger{
-			LogLevelCritical: log.New(
-				io.MultiWriter(loggerSettings.LogLevelConfigs[LogLevelCritical].Outputs...),
-				loggerSettings.LogLevelConfigs[LogLevelCritical].Prefix,
-				loggerSettings.LogLevelConfigs[LogLevelCritical].Flags,
-			),
-			LogLevelError: log.New(
-				io.MultiWriter(loggerSettings.LogLevelConfigs[LogLevelError].Outputs...),
-				loggerSettings.LogLevelConfigs[LogLevelError].Prefix,
-				loggerSettings.LogLevelConfigs[LogLevelError].Flags,
-			),
-			LogLevelWarning: log.New(
-				io.MultiWriter(loggerSettings.LogLevelConfigs[LogLevelWarning].Outputs...),
-				loggerSettings.LogLevelConfigs[LogLevelWarning].Prefix,
-				loggerSettings.LogLevelConfigs[LogLevelWarning].Flags,
-			),
-			LogLevelNotice: log.New(
-				io.MultiWriter(loggerSettings.LogLevelConfigs[LogLevelNotice].Outputs...),
-				loggerSettings.LogLevelConfigs[LogLevelNotice].Prefix,
-				loggerSettings.LogLevelConfigs[LogLevelNotice].Flags,
-			),
-			LogLevelInfo: log.New(
-				io.MultiWriter(loggerSettings.LogLevelConfigs[LogLevelInfo].Outputs...),
-				loggerSettings.LogLevelConfigs[LogLevelInfo].Prefix,
-				loggerSettings.LogLevelConfigs[LogLevelInfo].Flags,
-			),
-			LogLevelDebug: log.New(
-				io.MultiWriter(loggerSettings.LogLevelConfigs[LogLevelDebug].Outputs...),
-				loggerSettings.LogLevelConfigs[LogLevelDebug].Prefix,
-				loggerSettings.LogLevelConfigs[LogLevelDebug].Flags,
-			),
-		},
+	outputs := make(map[LogLevel]*levelOutput, len(loggerSettings.LogLevelConfigs))
+	for logLevel, conf := range loggerSettings.LogLevelConfigs {
+		var colors []bool
+		if conf.Format == FormatConsole {
+			colors = make([]bool, len(conf.Outputs))
+			for i, w := range conf.Outputs {
+				if loggerSettings.Color != nil {
+					colors[i] = *loggerSettings.Color
+				} else {
+					colors[i] = useColor(w)
+				}
+			}
+		}
+
+		outputs[logLevel] = &levelOutput{
+			outputs:      conf.Outputs,
+			prefix:       conf.Prefix,
+			flags:        conf.Flags,
+			caller:       conf.Caller,
+			trimPrefixes: loggerSettings.CallerTrimPrefixes,
+			timeFormat:   loggerSettings.TimeFormat,
+			location:     loggerSettings.TimeLocation,
+			format:       conf.Format,
+			stackTrace:   conf.StackTrace,
+			redact:       loggerSettings.Redaction,
+			sanitize:     loggerSettings.Sanitize,
+			multiline:    loggerSettings.Multiline,
+			colors:       colors,
+		}
+		if logLevel >= minLevel {
+			outputs[logLevel].hooks = loggerSettings.EntryHooks
+			outputs[logLevel].sink = loggerSettings.Sink
+		}
+		if conf.Sampling.enabled() {
+			outputs[logLevel].sampler = newSampler(*conf.Sampling)
+		}
+		if conf.RateLimit.enabled() {
+			outputs[logLevel].limiter = newRateLimiter(conf.RateLimit)
+		}
+		if conf.Dedup > 0 {
+			outputs[logLevel].deduper = newDeduper(conf.Dedup)
+		}
 	}
+	return outputs, loggerSettings
 }
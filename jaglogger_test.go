@@ -21,9 +21,9 @@ func TestNewLogger(t *testing.T) {
 	testLogFile := &os.File{}
 
 	defaultFlag := log.Ldate | log.Ltime | log.Llongfile
-	errOutputs := io.MultiWriter(os.Stderr)
-	nonErrOutputs := io.MultiWriter(os.Stdout)
-	noOutputs := io.MultiWriter()
+	errOutputs := []io.Writer{os.Stderr}
+	nonErrOutputs := []io.Writer{os.Stdout}
+	var noOutputs []io.Writer
 
 	tests := []struct {
 		name string
@@ -36,13 +36,15 @@ func TestNewLogger(t *testing.T) {
 				minLevel: LogLevelDebug,
 			},
 			want: logger{
-				outputs: map[LogLevel]*log.Logger{
-					LogLevelCritical: log.New(errOutputs, "[CRITICAL]", defaultFlag),
-					LogLevelError:    log.New(errOutputs, "[ERROR]", defaultFlag),
-					LogLevelWarning:  log.New(errOutputs, "[WARNING]", defaultFlag),
-					LogLevelNotice:   log.New(nonErrOutputs, "[NOTICE]", defaultFlag),
-					LogLevelInfo:     log.New(nonErrOutputs, "[INFO]", defaultFlag),
-					LogLevelDebug:    log.New(nonErrOutputs, "[DEBUG]", defaultFlag),
+				helpers:  &helperFuncs{},
+				cadences: &cadences{},
+				outputs: map[LogLevel]*levelOutput{
+					LogLevelCritical: {outputs: errOutputs, prefix: "[CRITICAL]", flags: defaultFlag, caller: CallerLongFile},
+					LogLevelError:    {outputs: errOutputs, prefix: "[ERROR]", flags: defaultFlag, caller: CallerLongFile},
+					LogLevelWarning:  {outputs: errOutputs, prefix: "[WARNING]", flags: defaultFlag, caller: CallerLongFile},
+					LogLevelNotice:   {outputs: nonErrOutputs, prefix: "[NOTICE]", flags: defaultFlag, caller: CallerLongFile},
+					LogLevelInfo:     {outputs: nonErrOutputs, prefix: "[INFO]", flags: defaultFlag, caller: CallerLongFile},
+					LogLevelDebug:    {outputs: nonErrOutputs, prefix: "[DEBUG]", flags: defaultFlag, caller: CallerLongFile},
 				},
 			},
 		},
@@ -52,13 +54,15 @@ func TestNewLogger(t *testing.T) {
 				minLevel: LogLevelInfo,
 			},
 			want: logger{
-				outputs: map[LogLevel]*log.Logger{
-					LogLevelCritical: log.New(errOutputs, "[CRITICAL]", defaultFlag),
-					LogLevelError:    log.New(errOutputs, "[ERROR]", defaultFlag),
-					LogLevelWarning:  log.New(errOutputs, "[WARNING]", defaultFlag),
-					LogLevelNotice:   log.New(nonErrOutputs, "[NOTICE]", defaultFlag),
-					LogLevelInfo:     log.New(nonErrOutputs, "[INFO]", defaultFlag),
-					LogLevelDebug:    log.New(noOutputs, "[DEBUG]", defaultFlag),
+				helpers:  &helperFuncs{},
+				cadences: &cadences{},
+				outputs: map[LogLevel]*levelOutput{
+					LogLevelCritical: {outputs: errOutputs, prefix: "[CRITICAL]", flags: defaultFlag, caller: CallerLongFile},
+					LogLevelError:    {outputs: errOutputs, prefix: "[ERROR]", flags: defaultFlag, caller: CallerLongFile},
+					LogLevelWarning:  {outputs: errOutputs, prefix: "[WARNING]", flags: defaultFlag, caller: CallerLongFile},
+					LogLevelNotice:   {outputs: nonErrOutputs, prefix: "[NOTICE]", flags: defaultFlag, caller: CallerLongFile},
+					LogLevelInfo:     {outputs: nonErrOutputs, prefix: "[INFO]", flags: defaultFlag, caller: CallerLongFile},
+					LogLevelDebug:    {outputs: noOutputs, prefix: "[DEBUG]", flags: defaultFlag, caller: CallerLongFile},
 				},
 			},
 		},
@@ -68,13 +72,15 @@ func TestNewLogger(t *testing.T) {
 				minLevel: LogLevelNotice,
 			},
 			want: logger{
-				outputs: map[LogLevel]*log.Logger{
-					LogLevelCritical: log.New(errOutputs, "[CRITICAL]", defaultFlag),
-					LogLevelError:    log.New(errOutputs, "[ERROR]", defaultFlag),
-					LogLevelWarning:  log.New(errOutputs, "[WARNING]", defaultFlag),
-					LogLevelNotice:   log.New(nonErrOutputs, "[NOTICE]", defaultFlag),
-					LogLevelInfo:     log.New(noOutputs, "[INFO]", defaultFlag),
-					LogLevelDebug:    log.New(noOutputs, "[DEBUG]", defaultFlag),
+				helpers:  &helperFuncs{},
+				cadences: &cadences{},
+				outputs: map[LogLevel]*levelOutput{
+					LogLevelCritical: {outputs: errOutputs, prefix: "[CRITICAL]", flags: defaultFlag, caller: CallerLongFile},
+					LogLevelError:    {outputs: errOutputs, prefix: "[ERROR]", flags: defaultFlag, caller: CallerLongFile},
+					LogLevelWarning:  {outputs: errOutputs, prefix: "[WARNING]", flags: defaultFlag, caller: CallerLongFile},
+					LogLevelNotice:   {outputs: nonErrOutputs, prefix: "[NOTICE]", flags: defaultFlag, caller: CallerLongFile},
+					LogLevelInfo:     {outputs: noOutputs, prefix: "[INFO]", flags: defaultFlag, caller: CallerLongFile},
+					LogLevelDebug:    {outputs: noOutputs, prefix: "[DEBUG]", flags: defaultFlag, caller: CallerLongFile},
 				},
 			},
 		},
@@ -84,13 +90,15 @@ func TestNewLogger(t *testing.T) {
 				minLevel: LogLevelWarning,
 			},
 			want: logger{
-				outputs: map[LogLevel]*log.Logger{
-					LogLevelCritical: log.New(errOutputs, "[CRITICAL]", defaultFlag),
-					LogLevelError:    log.New(errOutputs, "[ERROR]", defaultFlag),
-					LogLevelWarning:  log.New(errOutputs, "[WARNING]", defaultFlag),
-					LogLevelNotice:   log.New(noOutputs, "[NOTICE]", defaultFlag),
-					LogLevelInfo:     log.New(noOutputs, "[INFO]", defaultFlag),
-					LogLevelDebug:    log.New(noOutputs, "[DEBUG]", defaultFlag),
+				helpers:  &helperFuncs{},
+				cadences: &cadences{},
+				outputs: map[LogLevel]*levelOutput{
+					LogLevelCritical: {outputs: errOutputs, prefix: "[CRITICAL]", flags: defaultFlag, caller: CallerLongFile},
+					LogLevelError:    {outputs: errOutputs, prefix: "[ERROR]", flags: defaultFlag, caller: CallerLongFile},
+					LogLevelWarning:  {outputs: errOutputs, prefix: "[WARNING]", flags: defaultFlag, caller: CallerLongFile},
+					LogLevelNotice:   {outputs: noOutputs, prefix: "[NOTICE]", flags: defaultFlag, caller: CallerLongFile},
+					LogLevelInfo:     {outputs: noOutputs, prefix: "[INFO]", flags: defaultFlag, caller: CallerLongFile},
+					LogLevelDebug:    {outputs: noOutputs, prefix: "[DEBUG]", flags: defaultFlag, caller: CallerLongFile},
 				},
 			},
 		},
@@ -100,13 +108,15 @@ func TestNewLogger(t *testing.T) {
 				minLevel: LogLevelError,
 			},
 			want: logger{
-				outputs: map[LogLevel]*log.Logger{
-					LogLevelCritical: log.New(errOutputs, "[CRITICAL]", defaultFlag),
-					LogLevelError:    log.New(errOutputs, "[ERROR]", defaultFlag),
-					LogLevelWarning:  log.New(noOutputs, "[WARNING]", defaultFlag),
-					LogLevelNotice:   log.New(noOutputs, "[NOTICE]", defaultFlag),
-					LogLevelInfo:     log.New(noOutputs, "[INFO]", defaultFlag),
-					LogLevelDebug:    log.New(noOutputs, "[DEBUG]", defaultFlag),
+				helpers:  &helperFuncs{},
+				cadences: &cadences{},
+				outputs: map[LogLevel]*levelOutput{
+					LogLevelCritical: {outputs: errOutputs, prefix: "[CRITICAL]", flags: defaultFlag, caller: CallerLongFile},
+					LogLevelError:    {outputs: errOutputs, prefix: "[ERROR]", flags: defaultFlag, caller: CallerLongFile},
+					LogLevelWarning:  {outputs: noOutputs, prefix: "[WARNING]", flags: defaultFlag, caller: CallerLongFile},
+					LogLevelNotice:   {outputs: noOutputs, prefix: "[NOTICE]", flags: defaultFlag, caller: CallerLongFile},
+					LogLevelInfo:     {outputs: noOutputs, prefix: "[INFO]", flags: defaultFlag, caller: CallerLongFile},
+					LogLevelDebug:    {outputs: noOutputs, prefix: "[DEBUG]", flags: defaultFlag, caller: CallerLongFile},
 				},
 			},
 		},
@@ -116,13 +126,15 @@ func TestNewLogger(t *testing.T) {
 				minLevel: LogLevelCritical,
 			},
 			want: logger{
-				outputs: map[LogLevel]*log.Logger{
-					LogLevelCritical: log.New(errOutputs, "[CRITICAL]", defaultFlag),
-					LogLevelError:    log.New(noOutputs, "[ERROR]", defaultFlag),
-					LogLevelWarning:  log.New(noOutputs, "[WARNING]", defaultFlag),
-					LogLevelNotice:   log.New(noOutputs, "[NOTICE]", defaultFlag),
-					LogLevelInfo:     log.New(noOutputs, "[INFO]", defaultFlag),
-					LogLevelDebug:    log.New(noOutputs, "[DEBUG]", defaultFlag),
+				helpers:  &helperFuncs{},
+				cadences: &cadences{},
+				outputs: map[LogLevel]*levelOutput{
+					LogLevelCritical: {outputs: errOutputs, prefix: "[CRITICAL]", flags: defaultFlag, caller: CallerLongFile},
+					LogLevelError:    {outputs: noOutputs, prefix: "[ERROR]", flags: defaultFlag, caller: CallerLongFile},
+					LogLevelWarning:  {outputs: noOutputs, prefix: "[WARNING]", flags: defaultFlag, caller: CallerLongFile},
+					LogLevelNotice:   {outputs: noOutputs, prefix: "[NOTICE]", flags: defaultFlag, caller: CallerLongFile},
+					LogLevelInfo:     {outputs: noOutputs, prefix: "[INFO]", flags: defaultFlag, caller: CallerLongFile},
+					LogLevelDebug:    {outputs: noOutputs, prefix: "[DEBUG]", flags: defaultFlag, caller: CallerLongFile},
 				},
 			},
 		},
@@ -140,13 +152,15 @@ func TestNewLogger(t *testing.T) {
 				},
 			},
 			want: logger{
-				outputs: map[LogLevel]*log.Logger{
-					LogLevelCritical: log.New(io.MultiWriter(testLogFile), "[CRITICAL]", defaultFlag),
-					LogLevelError:    log.New(errOutputs, "[TEST_ERROR]", defaultFlag),
-					LogLevelWarning:  log.New(errOutputs, "[WARNING]", log.LstdFlags),
-					LogLevelNotice:   log.New(io.MultiWriter(ioutil.Discard), "[TEST_NOTICE]", defaultFlag),
-					LogLevelInfo:     log.New(nonErrOutputs, "[TEST_INFO]", log.LstdFlags),
-					LogLevelDebug:    log.New(io.MultiWriter(ioutil.Discard), "[TEST_DEBUG]", log.LstdFlags),
+				helpers:  &helperFuncs{},
+				cadences: &cadences{},
+				outputs: map[LogLevel]*levelOutput{
+					LogLevelCritical: {outputs: []io.Writer{testLogFile}, prefix: "[CRITICAL]", flags: defaultFlag, caller: CallerLongFile},
+					LogLevelError:    {outputs: errOutputs, prefix: "[TEST_ERROR]", flags: defaultFlag, caller: CallerLongFile},
+					LogLevelWarning:  {outputs: errOutputs, prefix: "[WARNING]", flags: log.LstdFlags, caller: CallerDisabled},
+					LogLevelNotice:   {outputs: []io.Writer{ioutil.Discard}, prefix: "[TEST_NOTICE]", flags: defaultFlag, caller: CallerLongFile},
+					LogLevelInfo:     {outputs: nonErrOutputs, prefix: "[TEST_INFO]", flags: log.LstdFlags, caller: CallerDisabled},
+					LogLevelDebug:    {outputs: []io.Writer{ioutil.Discard}, prefix: "[TEST_DEBUG]", flags: log.LstdFlags, caller: CallerDisabled},
 				},
 			},
 		},
@@ -161,13 +175,15 @@ func TestNewLogger(t *testing.T) {
 				},
 			},
 			want: logger{
-				outputs: map[LogLevel]*log.Logger{
-					LogLevelCritical: log.New(io.MultiWriter(testLogFile), "[CRITICAL]", log.LstdFlags),
-					LogLevelError:    log.New(io.MultiWriter(testLogFile), "[ERROR]", log.LstdFlags),
-					LogLevelWarning:  log.New(io.MultiWriter(testLogFile), "[WARNING]", log.LstdFlags),
-					LogLevelNotice:   log.New(io.MultiWriter(ioutil.Discard), "[NOTICE]", log.LstdFlags),
-					LogLevelInfo:     log.New(io.MultiWriter(ioutil.Discard), "[INFO]", log.LstdFlags),
-					LogLevelDebug:    log.New(io.MultiWriter(ioutil.Discard), "[DEBUG]", log.LstdFlags),
+				helpers:  &helperFuncs{},
+				cadences: &cadences{},
+				outputs: map[LogLevel]*levelOutput{
+					LogLevelCritical: {outputs: []io.Writer{testLogFile}, prefix: "[CRITICAL]", flags: log.LstdFlags, caller: CallerDisabled},
+					LogLevelError:    {outputs: []io.Writer{testLogFile}, prefix: "[ERROR]", flags: log.LstdFlags, caller: CallerDisabled},
+					LogLevelWarning:  {outputs: []io.Writer{testLogFile}, prefix: "[WARNING]", flags: log.LstdFlags, caller: CallerDisabled},
+					LogLevelNotice:   {outputs: []io.Writer{ioutil.Discard}, prefix: "[NOTICE]", flags: log.LstdFlags, caller: CallerDisabled},
+					LogLevelInfo:     {outputs: []io.Writer{ioutil.Discard}, prefix: "[INFO]", flags: log.LstdFlags, caller: CallerDisabled},
+					LogLevelDebug:    {outputs: []io.Writer{ioutil.Discard}, prefix: "[DEBUG]", flags: log.LstdFlags, caller: CallerDisabled},
 				},
 			},
 		},
@@ -466,6 +482,9 @@ func Test_logger_Infof(t *testing.T) {
 }
 
 func Test_logger_Debug(t *testing.T) {
+	if !DebugCompiled {
+		t.Skip("Debug is compiled out by the jaglogger_nodebug build tag")
+	}
 	type args struct {
 		v []any
 	}
@@ -494,6 +513,9 @@ func Test_logger_Debug(t *testing.T) {
 }
 
 func Test_logger_Debugf(t *testing.T) {
+	if !DebugCompiled {
+		t.Skip("Debug is compiled out by the jaglogger_nodebug build tag")
+	}
 	type args struct {
 		format string
 		v      []any
@@ -571,3 +593,32 @@ func TestLogLevel_String(t *testing.T) {
 		})
 	}
 }
+
+func TestParseLogLevel(t *testing.T) {
+	tests := []struct {
+		name    string
+		arg     string
+		want    LogLevel
+		wantErr bool
+	}{
+		{name: "Name", arg: "critical", want: LogLevelCritical},
+		{name: "Upper Case", arg: "ERROR", want: LogLevelError},
+		{name: "Bracketed", arg: "[WARNING]", want: LogLevelWarning},
+		{name: "Abbreviation", arg: "warn", want: LogLevelWarning},
+		{name: "Notice", arg: "Notice", want: LogLevelNotice},
+		{name: "Info", arg: "info", want: LogLevelInfo},
+		{name: "Debug", arg: "debug", want: LogLevelDebug},
+		{name: "Unknown", arg: "loud", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLogLevel(tt.arg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
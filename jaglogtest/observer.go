@@ -0,0 +1,167 @@
+// Package jaglogtest provides a Logger that records its entries, so tests can check what was logged
+// without parsing formatted output, and a Logger that writes its entries to the test.
+package jaglogtest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/williabk198/jaglogger"
+)
+
+// TestingT is the subset of testing.TB used by the assertion helpers.
+type TestingT interface {
+	Errorf(format string, args ...any)
+	Helper()
+}
+
+// Observer holds the entries logged by a Logger created with New.
+type Observer struct {
+	mu      sync.Mutex
+	entries []jaglogger.Entry
+}
+
+// New returns a Logger that records the entries at or above minLevel in the returned Observer,
+// instead of writing them anywhere. The options are applied after the ones set by New, which
+// means outputs can be added to also write the entries.
+func New(minLevel jaglogger.LogLevel, opts ...jaglogger.Option) (jaglogger.Logger, *Observer) {
+	o := &Observer{}
+	opts = append([]jaglogger.Option{
+		jaglogger.SetDefaultErrorOutputsOpt(nil),
+		jaglogger.SetDefaultNonErrorOutputOpt(nil),
+		jaglogger.SetEntryHookOpt(o.record),
+	}, opts...)
+	return jaglogger.NewLogger(minLevel, opts...), o
+}
+
+func (o *Observer) record(e jaglogger.Entry) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.entries = append(o.entries, e)
+}
+
+// Len returns the number of recorded entries.
+func (o *Observer) Len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.entries)
+}
+
+// All returns the recorded entries, in the order they were logged.
+func (o *Observer) All() []jaglogger.Entry {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]jaglogger.Entry(nil), o.entries...)
+}
+
+// TakeAll returns the recorded entries and removes them from the Observer.
+func (o *Observer) TakeAll() []jaglogger.Entry {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	entries := o.entries
+	o.entries = nil
+	return entries
+}
+
+// Filter returns the recorded entries for which keep returns true.
+func (o *Observer) Filter(keep func(jaglogger.Entry) bool) []jaglogger.Entry {
+	var filtered []jaglogger.Entry
+	for _, e := range o.All() {
+		if keep(e) {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
+
+// FilterLevel returns the recorded entries of the given log level.
+func (o *Observer) FilterLevel(level jaglogger.LogLevel) []jaglogger.Entry {
+	return o.Filter(func(e jaglogger.Entry) bool {
+		return e.Level == level
+	})
+}
+
+// FilterMessage returns the recorded entries whose message contains substr.
+func (o *Observer) FilterMessage(substr string) []jaglogger.Entry {
+	return o.Filter(func(e jaglogger.Entry) bool {
+		return strings.Contains(e.Message, substr)
+	})
+}
+
+// FilterField returns the recorded entries that have a field with the given key, and a value that's
+// deeply equal to value.
+func (o *Observer) FilterField(key string, value any) []jaglogger.Entry {
+	return o.Filter(func(e jaglogger.Entry) bool {
+		v, ok := FieldValue(e, key)
+		return ok && reflect.DeepEqual(v, value)
+	})
+}
+
+// FieldValue returns the value of the last field of e with the given key.
+func FieldValue(e jaglogger.Entry, key string) (any, bool) {
+	for i := len(e.Fields) - 1; i >= 0; i-- {
+		if e.Fields[i].Key == key {
+			return e.Fields[i].Value, true
+		}
+	}
+	return nil, false
+}
+
+// AssertLogged checks that an entry of the given log level with a message containing substr was
+// logged. It reports whether there was one.
+func (o *Observer) AssertLogged(t TestingT, level jaglogger.LogLevel, substr string) bool {
+	t.Helper()
+	for _, e := range o.FilterLevel(level) {
+		if strings.Contains(e.Message, substr) {
+			return true
+		}
+	}
+	t.Errorf("no %s entry with a message containing %q was logged; got:\n%s", level, substr, o)
+	return false
+}
+
+// AssertNotLogged checks that no entry of the given log level with a message containing substr
+// was logged. It reports whether there was none.
+func (o *Observer) AssertNotLogged(t TestingT, level jaglogger.LogLevel, substr string) bool {
+	t.Helper()
+	for _, e := range o.FilterLevel(level) {
+		if strings.Contains(e.Message, substr) {
+			t.Errorf("unexpected %s entry: %s", level, e.Message)
+			return false
+		}
+	}
+	return true
+}
+
+// AssertNoErrors checks that no Error or Critical entries were logged. It reports whether there
+// were none.
+func (o *Observer) AssertNoErrors(t TestingT) bool {
+	t.Helper()
+	errs := o.Filter(func(e jaglogger.Entry) bool {
+		return e.Level >= jaglogger.LogLevelError
+	})
+	for _, e := range errs {
+		t.Errorf("unexpected %s entry: %s", e.Level, e.Message)
+	}
+	return len(errs) == 0
+}
+
+// String returns the recorded entries, one per line, for use in failure messages.
+func (o *Observer) String() string {
+	var b strings.Builder
+	for _, e := range o.All() {
+		b.WriteString("\t")
+		b.WriteString(e.Level.String())
+		b.WriteString(e.Message)
+		for _, f := range e.Fields {
+			fmt.Fprintf(&b, " %s=%v", f.Key, f.Value)
+		}
+		b.WriteString("\n")
+	}
+	if b.Len() == 0 {
+		return "\t(nothing)\n"
+	}
+	return b.String()
+}
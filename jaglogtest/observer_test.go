@@ -0,0 +1,66 @@
+package jaglogtest
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/williabk198/jaglogger"
+)
+
+// fakeT records the failures reported by the assertion helpers.
+type fakeT struct {
+	errors []string
+}
+
+func (t *fakeT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func (t *fakeT) Helper() {}
+
+func TestObserver(t *testing.T) {
+	l, obs := New(jaglogger.LogLevelInfo)
+
+	l.Debug("ignored")
+	jaglogger.With(l, "attempt", 2).Info("connecting")
+	l.Errorf("request timeout after %ds", 5)
+
+	entries := obs.All()
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, jaglogger.LogLevelInfo, entries[0].Level)
+		assert.Equal(t, "connecting", entries[0].Message)
+		assert.Equal(t, []jaglogger.Field{{Key: "attempt", Value: 2}}, entries[0].Fields)
+		assert.True(t, strings.HasSuffix(entries[0].Caller.File, "observer_test.go"))
+		assert.Equal(t, "github.com/williabk198/jaglogger/jaglogtest.TestObserver", entries[0].Caller.Function)
+	}
+
+	assert.Len(t, obs.FilterLevel(jaglogger.LogLevelError), 1)
+	assert.Len(t, obs.FilterMessage("timeout"), 1)
+	assert.Len(t, obs.FilterField("attempt", 2), 1)
+	assert.Len(t, obs.FilterField("attempt", 3), 0)
+	assert.True(t, obs.AssertLogged(t, jaglogger.LogLevelError, "timeout"))
+	assert.True(t, obs.AssertNotLogged(t, jaglogger.LogLevelInfo, "timeout"))
+
+	assert.Len(t, obs.TakeAll(), 2)
+	assert.Equal(t, 0, obs.Len())
+	assert.True(t, obs.AssertNoErrors(t))
+}
+
+func TestObserver_Failures(t *testing.T) {
+	l, obs := New(jaglogger.LogLevelDebug)
+	l.Critical(errors.New("disk full"))
+	l.Info("started")
+
+	ft := new(fakeT)
+	assert.False(t, obs.AssertLogged(ft, jaglogger.LogLevelError, "disk"))
+	assert.False(t, obs.AssertNotLogged(ft, jaglogger.LogLevelInfo, "start"))
+	assert.False(t, obs.AssertNoErrors(ft))
+	assert.Equal(t, []string{
+		"no [ERROR] entry with a message containing \"disk\" was logged; got:\n\t[CRITICAL]disk full\n\t[INFO]started\n",
+		"unexpected [INFO] entry: started",
+		"unexpected [CRITICAL] entry: disk full",
+	}, ft.errors)
+}
@@ -0,0 +1,71 @@
+package jaglogtest
+
+import (
+	"log"
+	"sync"
+	"testing"
+
+	"github.com/williabk198/jaglogger"
+)
+
+// NewTestLogger returns a Logger that writes its entries at or above minLevel through t.Logf, so
+// they're attributed to the test and only shown when it fails or is run with -v. The jaglogger
+// functions that a log call goes through are marked with t.Helper, so the file and line that the
+// testing package reports is that of the log call. Entries logged after the test has completed,
+// e.g. by a goroutine that outlived it, are dropped instead of making the testing package panic.
+//
+// By default, entries are written without a timestamp or caller. The options are applied after
+// those defaults. jaglogger.Helper only affects the caller written by the logger; to make the
+// testing package skip a function, it has to call t.Helper itself.
+func NewTestLogger(t testing.TB, minLevel jaglogger.LogLevel, opts ...jaglogger.Option) jaglogger.Logger {
+	return NewFailingTestLogger(t, minLevel, 0, opts...)
+}
+
+// NewFailingTestLogger works like NewTestLogger, but writes the entries at or above failLevel
+// through t.Errorf, which fails the test on unexpected entries, such as those at LogLevelError and
+// above.
+func NewFailingTestLogger(t testing.TB, minLevel, failLevel jaglogger.LogLevel, opts ...jaglogger.Option) jaglogger.Logger {
+	sink := &testSink{TB: t, failLevel: failLevel}
+	t.Cleanup(sink.close)
+
+	opts = append([]jaglogger.Option{
+		jaglogger.SetDefaultFlagsOpt(log.Lmsgprefix),
+		jaglogger.SetDefaultErrorOutputsOpt(nil),
+		jaglogger.SetDefaultNonErrorOutputOpt(nil),
+	}, opts...)
+	return jaglogger.NewLogger(minLevel, append(opts, jaglogger.SetSinkOpt(sink))...)
+}
+
+// testSink writes formatted entries to a test until it completes. Its Helper method is the one of
+// the test, so that the jaglogger functions calling it are the ones marked as helpers.
+type testSink struct {
+	testing.TB
+	failLevel jaglogger.LogLevel
+
+	mu   sync.RWMutex
+	done bool
+}
+
+// close stops the sink from writing to the test. It's registered with t.Cleanup, which runs before
+// the testing package considers the test complete.
+func (s *testSink) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.done = true
+}
+
+func (s *testSink) WriteEntry(e jaglogger.Entry, line []byte) {
+	s.Helper()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.done {
+		return
+	}
+
+	if s.failLevel != 0 && e.Level >= s.failLevel {
+		s.Errorf("%s", line)
+	} else {
+		s.Logf("%s", line)
+	}
+}
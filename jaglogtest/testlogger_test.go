@@ -0,0 +1,174 @@
+package jaglogtest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/williabk198/jaglogger"
+)
+
+// fakeTB records what a test logger does with a testing.TB. Like the testing package, it reports
+// the first function that isn't a helper as the caller of Logf and Errorf.
+type fakeTB struct {
+	testing.TB
+
+	helpers  map[string]bool
+	cleanups []func()
+	logs     []string
+	errors   []string
+	callers  []string
+}
+
+func newFakeTB() *fakeTB {
+	return &fakeTB{helpers: map[string]bool{}}
+}
+
+func (tb *fakeTB) Helper() {
+	pc, _, _, _ := runtime.Caller(1)
+	tb.helpers[runtime.FuncForPC(pc).Name()] = true
+}
+
+func (tb *fakeTB) Cleanup(f func()) {
+	tb.cleanups = append(tb.cleanups, f)
+}
+
+func (tb *fakeTB) Logf(format string, args ...any) {
+	tb.logs = append(tb.logs, fmt.Sprintf(format, args...))
+	tb.callers = append(tb.callers, tb.caller())
+}
+
+func (tb *fakeTB) Errorf(format string, args ...any) {
+	tb.errors = append(tb.errors, fmt.Sprintf(format, args...))
+	tb.callers = append(tb.callers, tb.caller())
+}
+
+func (tb *fakeTB) caller() string {
+	pcs := make([]uintptr, 32)
+	// Skip runtime.Callers, caller and Logf or Errorf
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !tb.helpers[frame.Function] || !more {
+			return frame.Function
+		}
+	}
+}
+
+func (tb *fakeTB) finish() {
+	for i := len(tb.cleanups) - 1; i >= 0; i-- {
+		tb.cleanups[i]()
+	}
+}
+
+func TestNewTestLogger(t *testing.T) {
+	tb := newFakeTB()
+	l := NewTestLogger(tb, jaglogger.LogLevelInfo)
+
+	l.Debug("ignored")
+	l.Info("first")
+	jaglogger.With(l, "a", 1).Errorf("second: %v", errors.New("failed"))
+
+	assert.Equal(t, []string{"[INFO]first", "[ERROR]second: failed a=1"}, tb.logs)
+	assert.Empty(t, tb.errors)
+	for _, caller := range tb.callers {
+		assert.Equal(t, "github.com/williabk198/jaglogger/jaglogtest.TestNewTestLogger", caller)
+	}
+}
+
+func TestNewTestLogger_Writers(t *testing.T) {
+	tb := newFakeTB()
+	l := NewTestLogger(tb, jaglogger.LogLevelInfo)
+
+	jaglogger.StdLogger(l, jaglogger.LogLevelWarning).Print("first")
+	w := jaglogger.Writer(l, jaglogger.LogLevelInfo)
+	io.WriteString(w, "second\nthird")
+	w.Close()
+
+	assert.Equal(t, []string{"[WARNING]first", "[INFO]second", "[INFO]third"}, tb.logs)
+}
+
+func TestNewFailingTestLogger(t *testing.T) {
+	tb := newFakeTB()
+	l := NewFailingTestLogger(tb, jaglogger.LogLevelDebug, jaglogger.LogLevelError)
+
+	l.Warning("expected")
+	l.Critical("unexpected")
+
+	assert.Equal(t, []string{"[WARNING]expected"}, tb.logs)
+	assert.Equal(t, []string{"[CRITICAL]unexpected"}, tb.errors)
+	assert.Equal(t, "github.com/williabk198/jaglogger/jaglogtest.TestNewFailingTestLogger", tb.callers[1])
+}
+
+func TestNewTestLogger_AfterCompletion(t *testing.T) {
+	tb := newFakeTB()
+	l := NewTestLogger(tb, jaglogger.LogLevelInfo)
+
+	l.Info("before")
+	tb.finish()
+	l.Info("after")
+
+	assert.Equal(t, []string{"[INFO]before"}, tb.logs)
+}
+
+func TestNewTestLogger_Options(t *testing.T) {
+	tb := newFakeTB()
+	output := new(bytes.Buffer)
+	var hooked []jaglogger.Entry
+	l := NewTestLogger(
+		tb,
+		jaglogger.LogLevelInfo,
+		jaglogger.SetDefaultFormatOpt(jaglogger.FormatLogfmt),
+		jaglogger.SetDefaultNonErrorOutputOpt([]io.Writer{output}),
+		jaglogger.SetEntryHookOpt(func(e jaglogger.Entry) { hooked = append(hooked, e) }),
+	)
+
+	l.Info("test")
+
+	assert.Equal(t, []string{"level=info msg=test"}, tb.logs)
+	assert.Equal(t, "level=info msg=test\n", output.String())
+	assert.Len(t, hooked, 1)
+}
+
+func TestNewTestLogger_Recover(t *testing.T) {
+	tb := newFakeTB()
+	l := NewTestLogger(tb, jaglogger.LogLevelInfo)
+
+	func() {
+		defer jaglogger.Recover(l, jaglogger.RecoverSwallowOpt())
+		panic("boom")
+	}()
+
+	if assert.Len(t, tb.logs, 1) {
+		assert.True(t, strings.HasPrefix(tb.logs[0], "[CRITICAL]panic: boom\n"), tb.logs[0])
+		assert.Contains(t, tb.logs[0], "TestNewTestLogger_Recover")
+	}
+}
+
+func TestNewTestLogger_Subtest(t *testing.T) {
+	l := NewTestLogger(t, jaglogger.LogLevelDebug)
+	l.Debug("written through t.Logf")
+
+	t.Run("Subtest", func(t *testing.T) {
+		NewTestLogger(t, jaglogger.LogLevelDebug).Info("written through the subtest's t.Logf")
+	})
+}
+
+func TestNewTestLogger_Summaries(t *testing.T) {
+	tb := newFakeTB()
+	l := NewFailingTestLogger(tb, jaglogger.LogLevelInfo, jaglogger.LogLevelWarning, jaglogger.SetDefaultDedupOpt(time.Minute))
+
+	for i := 0; i < 3; i++ {
+		l.Warning("same")
+	}
+	assert.NoError(t, jaglogger.Close(l))
+
+	assert.Equal(t, []string{"[WARNING]same", "[WARNING]message repeated 2 times repeated=2"}, tb.errors)
+}
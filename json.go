@@ -0,0 +1,191 @@
+package jaglogger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// appendJSON appends e to buf as a JSON object on a single line:
+//
+//	{"time":"2022-07-03T22:05:03-05:00","level":"info","caller":"main.go:8","msg":"a message","key":"value"}
+func (lo *levelOutput) appendJSON(buf []byte, e *Entry) []byte {
+	buf = append(buf, '{')
+	if lo.hasTime() && !e.Time.IsZero() {
+		buf = append(buf, `"`+timeKey+`":`...)
+		buf = appendQuoted(buf, string(lo.appendStructuredTime(nil, e.Time)))
+		buf = append(buf, ',')
+	}
+
+	buf = append(buf, `"`+levelKey+`":"`...)
+	buf = append(buf, e.Level.name()...)
+	buf = append(buf, '"')
+
+	if lo.caller&CallerDisabled == 0 && e.Caller.Defined {
+		file, line, function := lo.callerParts(e.Caller)
+		if lo.caller&callerFileFormats != 0 {
+			buf = append(buf, `,"`+callerKey+`":`...)
+			buf = appendQuoted(buf, file+":"+strconv.Itoa(line))
+		}
+		if lo.caller&CallerFunction != 0 {
+			buf = append(buf, `,"`+functionKey+`":`...)
+			buf = appendQuoted(buf, function)
+		}
+	}
+
+	buf = append(buf, `,"`+messageKey+`":`...)
+	buf = appendQuoted(buf, strings.TrimSuffix(e.Message, "\n"))
+
+	buf = appendJSONFields(buf, e.Fields)
+	if len(e.Stack) > 0 {
+		buf = append(buf, `,"`+stackKey+`":`...)
+		buf = e.Stack.appendJSON(buf)
+	}
+	return append(buf, '}', '\n')
+}
+
+// appendJSONFields appends each field to buf as ,"key":value.
+func appendJSONFields(buf []byte, fields []Field) []byte {
+	for _, f := range fields {
+		buf = append(buf, ',')
+		buf = appendQuoted(buf, f.Key)
+		buf = append(buf, ':')
+		buf = appendJSONValue(buf, f.Value)
+	}
+	return buf
+}
+
+// appendJSONValue appends v to buf as a JSON value. Numbers and booleans are written as such, and
+// anything that can't be marshaled by the encoding/json package is written as a string.
+func appendJSONValue(buf []byte, v any) []byte {
+	if b, ok := appendNumber(buf, v); ok {
+		return b
+	}
+
+	switch v := v.(type) {
+	case nil:
+		return append(buf, "null"...)
+	case string:
+		return appendQuoted(buf, v)
+	case float32:
+		return appendJSONFloat(buf, float64(v), 32)
+	case float64:
+		return appendJSONFloat(buf, v, 64)
+	case time.Time:
+		return appendQuoted(buf, v.Format(time.RFC3339Nano))
+	case time.Duration:
+		return appendQuoted(buf, v.String())
+	case []string:
+		return appendStrings(buf, v)
+	case Stack:
+		return v.appendJSON(buf)
+	case json.Number:
+		return append(buf, v...)
+	}
+
+	if obj, ok := objectValue(v); ok {
+		buf = append(buf, '{')
+		start := len(buf)
+		buf = appendJSONFields(buf, obj.LogFields())
+		if len(buf) > start {
+			// Drop the comma in front of the first field
+			copy(buf[start:], buf[start+1:])
+			buf = buf[:len(buf)-1]
+		}
+		return append(buf, '}')
+	}
+
+	switch v := v.(type) {
+	case error, fmt.Stringer:
+		return appendQuoted(buf, fmt.Sprint(v))
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return appendQuoted(buf, fmt.Sprint(v))
+		}
+		return append(buf, b...)
+	}
+}
+
+// appendJSONFloat appends f to buf. NaN and infinities, which JSON has no numbers for, are written as strings.
+func appendJSONFloat(buf []byte, f float64, bitSize int) []byte {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return appendQuoted(buf, strconv.FormatFloat(f, 'g', -1, bitSize))
+	}
+	return strconv.AppendFloat(buf, f, 'g', -1, bitSize)
+}
+
+// ParseJSON parses a single line written in the FormatJSON format. The time, level, caller, func and
+// msg keys are used to fill in the properties of the Entry, and every other key/value pair becomes a
+// Field. Field values are decoded like encoding/json decodes into an interface{}, except that numbers
+// are decoded as a json.Number.
+func ParseJSON(line string) (Entry, error) {
+	var e Entry
+	dec := json.NewDecoder(strings.NewReader(line))
+	dec.UseNumber()
+
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return e, &ParseError{Msg: "not a JSON object"}
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return e, &ParseError{Msg: "invalid JSON: " + err.Error()}
+		}
+		key := tok.(string)
+
+		var value any
+		if err := dec.Decode(&value); err != nil {
+			return e, &ParseError{Msg: "invalid JSON: " + err.Error()}
+		}
+
+		if s, ok := value.(string); ok && setEntryProperty(&e, key, s) {
+			continue
+		}
+		e.Fields = append(e.Fields, Field{Key: key, Value: value})
+	}
+	if _, err := dec.Token(); err != nil {
+		return e, &ParseError{Msg: "invalid JSON: " + err.Error()}
+	}
+	return e, nil
+}
+
+// setEntryProperty sets the property of e that the structured formats write under the given key.
+// It returns false if key isn't one of those, or value isn't valid for the property.
+func setEntryProperty(e *Entry, key, value string) bool {
+	switch key {
+	case timeKey:
+		if t, ok := parseTimestamp(value); ok {
+			e.Time = t
+			return true
+		}
+	case levelKey:
+		if level, err := ParseLogLevel(value); err == nil {
+			e.Level = level
+			return true
+		}
+	case callerKey:
+		if i := strings.LastIndexByte(value, ':'); i >= 0 {
+			if line, err := strconv.Atoi(value[i+1:]); err == nil {
+				e.Caller.File, e.Caller.Line, e.Caller.Defined = value[:i], line, true
+				return true
+			}
+		}
+	case functionKey:
+		e.Caller.Function, e.Caller.Defined = value, true
+		return true
+	case messageKey:
+		e.Message = value
+		return true
+	}
+	return false
+}
+
+// NewJSONScanner returns a LineScanner that reads entries written in the FormatJSON format from r.
+func NewJSONScanner(r io.Reader) *LineScanner {
+	return NewLineScanner(r, ParseJSON)
+}
@@ -0,0 +1,180 @@
+package jaglogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"math"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// funcPointerPattern matches the quoted pointers that values without a JSON encoding are written as.
+var funcPointerPattern = regexp.MustCompile(`"0x[0-9a-f]+"`)
+
+func Test_logger_JSONFormat(t *testing.T) {
+	type args struct {
+		conf          Config
+		keysAndValues []any
+		msg           string
+	}
+
+	clock := func() time.Time {
+		return time.Date(2022, time.July, 3, 22, 5, 3, 0, time.FixedZone("EST", -5*60*60))
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "Plain",
+			args: args{conf: Config{Flags: log.Ldate}, msg: "test"},
+			want: `{"time":"2022-07-03T22:05:03-05:00","level":"info","msg":"test"}` + "\n",
+		},
+		{
+			name: "Caller",
+			args: args{conf: Config{Flags: log.Lmsgprefix, Caller: CallerShortFile | CallerFunction}, msg: "test"},
+			want: `{"level":"info","caller":"json_test.go:0","func":"github.com/williabk198/jaglogger.Test_logger_JSONFormat.func0","msg":"test"}` + "\n",
+		},
+		{
+			name: "Escaping",
+			args: args{conf: Config{Flags: log.Lmsgprefix}, msg: "say \"hi\"\n\x1b[31m\\"},
+			want: `{"level":"info","msg":"say \"hi\"\n\u001b[31m\\"}` + "\n",
+		},
+		{
+			name: "Fields",
+			args: args{
+				conf: Config{Flags: log.Lmsgprefix},
+				keysAndValues: []any{
+					"int", 1, "float", 1.5, "nan", math.NaN(), "bool", true, "nil", nil,
+					"err", errors.New("failed"), "duration", time.Second, "slice", []int{1, 2}, "func", func() {},
+				},
+				msg: "test",
+			},
+			want: `{"level":"info","msg":"test","int":1,"float":1.5,"nan":"NaN","bool":true,"nil":null,` +
+				`"err":"failed","duration":"1s","slice":[1,2],"func":"0x0"}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loggerOutput := new(bytes.Buffer)
+			tt.args.conf.Outputs = []io.Writer{loggerOutput}
+			tt.args.conf.Format = FormatJSON
+			l := NewLogger(LogLevelInfo, SetClockOpt(clock), SetInfoLoggerOpt(tt.args.conf))
+
+			With(l, tt.args.keysAndValues...).Info(tt.args.msg)
+			got := callerLinePattern.ReplaceAllString(loggerOutput.String(), "${1}0")
+			got = funcPointerPattern.ReplaceAllString(got, `"0x0"`)
+			assert.Equal(t, tt.want, got)
+			assert.True(t, json.Valid([]byte(got)), "invalid JSON: %s", got)
+		})
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		want    Entry
+		wantErr string
+	}{
+		{
+			name: "Entry Properties",
+			line: `{"time":"2022-07-03T22:05:03-05:00","level":"warning","caller":"main.go:8","func":"main.main","msg":"test test"}`,
+			want: Entry{
+				Level:   LogLevelWarning,
+				Time:    time.Date(2022, time.July, 3, 22, 5, 3, 0, time.FixedZone("", -5*60*60)),
+				Caller:  Caller{File: "main.go", Line: 8, Function: "main.main", Defined: true},
+				Message: "test test",
+			},
+		},
+		{
+			name: "Fields",
+			line: `{"level":"info","msg":"test","b":1,"a":"x","c":[true,null],"d":{"e":1.5}}`,
+			want: Entry{
+				Level:   LogLevelInfo,
+				Message: "test",
+				Fields: []Field{
+					{Key: "b", Value: json.Number("1")},
+					{Key: "a", Value: "x"},
+					{Key: "c", Value: []any{true, nil}},
+					{Key: "d", Value: map[string]any{"e": json.Number("1.5")}},
+				},
+			},
+		},
+		{
+			name: "Unrecognized Values",
+			line: `{"time":1.5,"level":"loud","msg":"test"}`,
+			want: Entry{
+				Message: "test",
+				Fields: []Field{
+					{Key: "time", Value: json.Number("1.5")},
+					{Key: "level", Value: "loud"},
+				},
+			},
+		},
+		{
+			name:    "Not An Object",
+			line:    `level=info msg=test`,
+			wantErr: "jaglogger: not a JSON object",
+		},
+		{
+			name:    "Truncated",
+			line:    `{"level":"info","msg":"te`,
+			want:    Entry{Level: LogLevelInfo},
+			wantErr: "jaglogger: invalid JSON: unexpected EOF",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseJSON(tt.line)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.True(t, tt.want.Time.Equal(got.Time), "want time %s, got %s", tt.want.Time, got.Time)
+			tt.want.Time, got.Time = time.Time{}, time.Time{}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	loggerOutput := new(bytes.Buffer)
+	clock := func() time.Time {
+		return time.Date(2022, time.July, 3, 22, 5, 3, 0, time.UTC)
+	}
+	l := NewLogger(
+		LogLevelInfo,
+		SetClockOpt(clock),
+		SetInfoLoggerOpt(Config{Outputs: []io.Writer{loggerOutput}, Format: FormatJSON, Caller: CallerShortFile}),
+	)
+
+	msg := "multi\nline \"message\"\twith = signs"
+	With(l, "key", "a value", "n", 42).Info(msg)
+	l.Info("second")
+
+	s := NewJSONScanner(loggerOutput)
+	assert.True(t, s.Scan())
+	got, err := s.Entry()
+	assert.NoError(t, err)
+	assert.Equal(t, LogLevelInfo, got.Level)
+	assert.True(t, clock().Equal(got.Time))
+	assert.Equal(t, "json_test.go", got.Caller.File)
+	assert.Equal(t, msg, got.Message)
+	assert.Equal(t, []Field{{Key: "key", Value: "a value"}, {Key: "n", Value: json.Number("42")}}, got.Fields)
+
+	assert.True(t, s.Scan())
+	got, err = s.Entry()
+	assert.NoError(t, err)
+	assert.Equal(t, "second", got.Message)
+	assert.False(t, s.Scan())
+}
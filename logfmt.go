@@ -0,0 +1,208 @@
+package jaglogger
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// The keys of the entry properties in the structured formats
+const (
+	timeKey     = "time"
+	levelKey    = "level"
+	callerKey   = "caller"
+	functionKey = "func"
+	messageKey  = "msg"
+	stackKey    = "stack"
+)
+
+// appendLogfmt appends e to buf in the logfmt format:
+//
+//	time=2022-07-03T22:05:03-05:00 level=info caller=main.go:8 msg="a message" key=value
+func (lo *levelOutput) appendLogfmt(buf []byte, e *Entry) []byte {
+	start := len(buf)
+	if lo.hasTime() && !e.Time.IsZero() {
+		buf = append(buf, timeKey+"="...)
+		buf = appendLogfmtString(buf, string(lo.appendStructuredTime(nil, e.Time)))
+	}
+
+	buf = appendLogfmtSeparator(buf, start)
+	buf = append(buf, levelKey+"="...)
+	buf = append(buf, e.Level.name()...)
+
+	if lo.caller&CallerDisabled == 0 && e.Caller.Defined {
+		file, line, function := lo.callerParts(e.Caller)
+		if lo.caller&callerFileFormats != 0 {
+			buf = append(buf, " "+callerKey+"="...)
+			buf = appendLogfmtString(buf, file+":"+strconv.Itoa(line))
+		}
+		if lo.caller&CallerFunction != 0 {
+			buf = append(buf, " "+functionKey+"="...)
+			buf = appendLogfmtString(buf, function)
+		}
+	}
+
+	buf = append(buf, " "+messageKey+"="...)
+	buf = appendLogfmtString(buf, strings.TrimSuffix(e.Message, "\n"))
+
+	for _, f := range flattenFields(e.Fields) {
+		buf = append(buf, ' ')
+		buf = appendLogfmtKey(buf, f.Key)
+		buf = append(buf, '=')
+		if b, ok := appendNumber(buf, f.Value); ok {
+			buf = b
+		} else {
+			buf = appendLogfmtString(buf, valueString(f.Value))
+		}
+	}
+	if len(e.Stack) > 0 {
+		buf = append(buf, " "+stackKey+"="...)
+		buf = appendLogfmtString(buf, e.Stack.String())
+	}
+	return append(buf, '\n')
+}
+
+func appendLogfmtSeparator(buf []byte, start int) []byte {
+	if len(buf) > start {
+		return append(buf, ' ')
+	}
+	return buf
+}
+
+// appendLogfmtKey appends key to buf, replacing the characters that can't be part of a key with
+// underscores.
+func appendLogfmtKey(buf []byte, key string) []byte {
+	if key == "" {
+		return append(buf, '_')
+	}
+	for _, r := range key {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || r == 0x7f {
+			buf = append(buf, '_')
+		} else {
+			buf = utf8.AppendRune(buf, r)
+		}
+	}
+	return buf
+}
+
+// appendLogfmtString appends s to buf, quoting it when needed.
+func appendLogfmtString(buf []byte, s string) []byte {
+	if needsQuoting(s) {
+		return appendQuoted(buf, s)
+	}
+	return append(buf, s...)
+}
+
+const hexDigits = "0123456789abcdef"
+
+// appendQuoted appends s to buf as a double quoted string, using the same escape sequences as JSON.
+// Invalid UTF-8 is replaced with the Unicode replacement character.
+func appendQuoted(buf []byte, s string) []byte {
+	buf = append(buf, '"')
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+		switch {
+		case r == '"' || r == '\\':
+			buf = append(buf, '\\', byte(r))
+		case r == '\n':
+			buf = append(buf, '\\', 'n')
+		case r == '\r':
+			buf = append(buf, '\\', 'r')
+		case r == '\t':
+			buf = append(buf, '\\', 't')
+		case r < ' ' || r == 0x7f:
+			buf = append(buf, '\\', 'u', '0', '0', hexDigits[r>>4], hexDigits[r&0xf])
+		case r == utf8.RuneError && size == 1:
+			buf = append(buf, "\ufffd"...)
+		default:
+			buf = append(buf, s[i-size:i]...)
+		}
+	}
+	return append(buf, '"')
+}
+
+// ParseLogfmt parses a single line written in the FormatLogfmt format. The time, level, caller,
+// func and msg keys are used to fill in the properties of the Entry, and every other key/value pair
+// becomes a Field with a string value, or a nil value for keys without one.
+func ParseLogfmt(line string) (Entry, error) {
+	var e Entry
+	err := scanLogfmtPairs(line, func(key string, value *string) {
+		if value == nil {
+			e.Fields = append(e.Fields, Field{Key: key})
+			return
+		}
+
+		if setEntryProperty(&e, key, *value) {
+			return
+		}
+		e.Fields = append(e.Fields, Field{Key: key, Value: *value})
+	})
+	return e, err
+}
+
+// scanLogfmtPairs calls fn with each key/value pair in line. The value is nil for keys without one.
+func scanLogfmtPairs(line string, fn func(key string, value *string)) error {
+	for i := 0; i < len(line); {
+		if line[i] == ' ' || line[i] == '\t' {
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(line) && line[i] > ' ' && line[i] != '=' && line[i] != '"' {
+			i++
+		}
+		if i == start {
+			return &ParseError{Msg: "unexpected " + strconv.QuoteRune(rune(line[i])) + " at column " + strconv.Itoa(i+1)}
+		}
+		key := line[start:i]
+
+		if i >= len(line) || line[i] != '=' {
+			fn(key, nil)
+			continue
+		}
+		i++
+
+		var value string
+		if i < len(line) && line[i] == '"' {
+			end := endOfQuoted(line, i)
+			if end < 0 {
+				return &ParseError{Msg: "unterminated quoted value for key " + strconv.Quote(key)}
+			}
+			if err := json.Unmarshal([]byte(line[i:end]), &value); err != nil {
+				return &ParseError{Msg: "invalid quoted value for key " + strconv.Quote(key)}
+			}
+			i = end
+		} else {
+			start = i
+			for i < len(line) && line[i] > ' ' {
+				i++
+			}
+			value = line[start:i]
+		}
+		fn(key, &value)
+	}
+	return nil
+}
+
+// endOfQuoted returns the index just past the closing quote of the quoted string starting at
+// line[start], or -1 if it's never closed.
+func endOfQuoted(line string, start int) int {
+	for i := start + 1; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// NewLogfmtScanner returns a LineScanner that reads entries written in the FormatLogfmt format from r.
+func NewLogfmtScanner(r io.Reader) *LineScanner {
+	return NewLineScanner(r, ParseLogfmt)
+}
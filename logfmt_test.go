@@ -0,0 +1,207 @@
+package jaglogger
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// callerLinePattern matches the line numbers and closure numbers of callers, so they can be replaced
+// with 0 for exact comparisons.
+var callerLinePattern = regexp.MustCompile(`(\.go:|\.func)\d+`)
+
+func Test_logger_LogfmtFormat(t *testing.T) {
+	type args struct {
+		conf          Config
+		keysAndValues []any
+		msg           string
+	}
+
+	clock := func() time.Time {
+		return time.Date(2022, time.July, 3, 22, 5, 3, 0, time.FixedZone("EST", -5*60*60))
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "Plain",
+			args: args{conf: Config{Flags: log.Ldate}, msg: "test"},
+			want: "time=2022-07-03T22:05:03-05:00 level=info msg=test\n",
+		},
+		{
+			name: "No Time",
+			args: args{conf: Config{Flags: log.Lmsgprefix}, msg: "test test"},
+			want: "level=info msg=\"test test\"\n",
+		},
+		{
+			name: "UTC Microseconds",
+			args: args{conf: Config{Flags: log.Ltime | log.Lmicroseconds | log.LUTC}, msg: "test"},
+			want: "time=2022-07-04T03:05:03.000000Z level=info msg=test\n",
+		},
+		{
+			name: "Caller",
+			args: args{conf: Config{Flags: log.Lmsgprefix, Caller: CallerShortFile | CallerFunction}, msg: "test"},
+			want: "level=info caller=logfmt_test.go:0 func=github.com/williabk198/jaglogger.Test_logger_LogfmtFormat.func0 msg=test\n",
+		},
+		{
+			name: "Escaping",
+			args: args{conf: Config{Flags: log.Lmsgprefix}, msg: "say \"hi\"\n\x1b[31m\\"},
+			want: "level=info msg=\"say \\\"hi\\\"\\n\\u001b[31m\\\\\"\n",
+		},
+		{
+			name: "Fields",
+			args: args{
+				conf:          Config{Flags: log.Lmsgprefix},
+				keysAndValues: []any{"a", 1, "b", "", "c", "x=y", "bad key", "ok", "d", "\xff"},
+				msg:           "test",
+			},
+			want: "level=info msg=test a=1 b=\"\" c=\"x=y\" bad_key=ok d=\"\ufffd\"\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loggerOutput := new(bytes.Buffer)
+			tt.args.conf.Outputs = []io.Writer{loggerOutput}
+			tt.args.conf.Format = FormatLogfmt
+			l := NewLogger(LogLevelInfo, SetClockOpt(clock), SetInfoLoggerOpt(tt.args.conf))
+
+			With(l, tt.args.keysAndValues...).Info(tt.args.msg)
+			got := callerLinePattern.ReplaceAllString(loggerOutput.String(), "${1}0")
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseLogfmt(t *testing.T) {
+	tests := []struct {
+		name    string
+		line    string
+		want    Entry
+		wantErr string
+	}{
+		{
+			name: "Entry Properties",
+			line: `time=2022-07-03T22:05:03-05:00 level=warning caller=main.go:8 func=main.main msg="test test"`,
+			want: Entry{
+				Level:   LogLevelWarning,
+				Time:    time.Date(2022, time.July, 3, 22, 5, 3, 0, time.FixedZone("", -5*60*60)),
+				Caller:  Caller{File: "main.go", Line: 8, Function: "main.main", Defined: true},
+				Message: "test test",
+			},
+		},
+		{
+			name: "Unix Milliseconds",
+			line: `time=1656903903123 level=info msg=test`,
+			want: Entry{Level: LogLevelInfo, Time: time.UnixMilli(1656903903123), Message: "test"},
+		},
+		{
+			name: "Fields",
+			line: `level=info msg=test a=1 b="" c="x=y" flag d="\u001b\"\\"`,
+			want: Entry{
+				Level:   LogLevelInfo,
+				Message: "test",
+				Fields: []Field{
+					{Key: "a", Value: "1"},
+					{Key: "b", Value: ""},
+					{Key: "c", Value: "x=y"},
+					{Key: "flag"},
+					{Key: "d", Value: "\x1b\"\\"},
+				},
+			},
+		},
+		{
+			name: "Unrecognized Values",
+			line: `time=yesterday level=loud caller=nowhere msg=test`,
+			want: Entry{
+				Message: "test",
+				Fields: []Field{
+					{Key: "time", Value: "yesterday"},
+					{Key: "level", Value: "loud"},
+					{Key: "caller", Value: "nowhere"},
+				},
+			},
+		},
+		{
+			name:    "Unterminated Quote",
+			line:    `level=info msg="test`,
+			want:    Entry{Level: LogLevelInfo},
+			wantErr: `jaglogger: unterminated quoted value for key "msg"`,
+		},
+		{
+			name:    "Missing Key",
+			line:    `level=info =test`,
+			want:    Entry{Level: LogLevelInfo},
+			wantErr: `jaglogger: unexpected '=' at column 12`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLogfmt(tt.line)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.True(t, tt.want.Time.Equal(got.Time), "want time %s, got %s", tt.want.Time, got.Time)
+			tt.want.Time, got.Time = time.Time{}, time.Time{}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLogfmtScanner(t *testing.T) {
+	input := "level=info msg=first\n\nlevel=error msg=\"second\n" +
+		"level=debug msg=third a=1\n"
+
+	s := NewLogfmtScanner(strings.NewReader(input))
+
+	assert.True(t, s.Scan())
+	e, err := s.Entry()
+	assert.NoError(t, err)
+	assert.Equal(t, Entry{Level: LogLevelInfo, Message: "first"}, e)
+
+	assert.True(t, s.Scan())
+	_, err = s.Entry()
+	assert.EqualError(t, err, `jaglogger: line 3: unterminated quoted value for key "msg"`)
+	assert.Equal(t, `level=error msg="second`, s.Text())
+
+	assert.True(t, s.Scan())
+	e, err = s.Entry()
+	assert.NoError(t, err)
+	assert.Equal(t, Entry{Level: LogLevelDebug, Message: "third", Fields: []Field{{Key: "a", Value: "1"}}}, e)
+
+	assert.False(t, s.Scan())
+	assert.NoError(t, s.Err())
+}
+
+func TestLogfmtRoundTrip(t *testing.T) {
+	loggerOutput := new(bytes.Buffer)
+	clock := func() time.Time {
+		return time.Date(2022, time.July, 3, 22, 5, 3, 0, time.UTC)
+	}
+	l := NewLogger(
+		LogLevelInfo,
+		SetClockOpt(clock),
+		SetInfoLoggerOpt(Config{Outputs: []io.Writer{loggerOutput}, Format: FormatLogfmt, Caller: CallerShortFile}),
+	)
+
+	msg := "multi\nline \"message\"\twith = signs"
+	With(l, "key", "a value").Info(msg)
+
+	got, err := ParseLogfmt(strings.TrimSuffix(loggerOutput.String(), "\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, LogLevelInfo, got.Level)
+	assert.True(t, clock().Equal(got.Time))
+	assert.Equal(t, "logfmt_test.go", got.Caller.File)
+	assert.Equal(t, msg, got.Message)
+	assert.Equal(t, []Field{{Key: "key", Value: "a value"}}, got.Fields)
+}
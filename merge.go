@@ -0,0 +1,147 @@
+package jaglogger
+
+import (
+	"container/heap"
+	"time"
+)
+
+// MergeSource is one of the log streams merged by a MergeScanner.
+type MergeSource struct {
+	// Name identifies the source, such as the name of the file the entries are read from.
+	Name    string
+	Scanner Scanner
+}
+
+// MergeScanner is a Scanner that merges the entries of several sources in chronological order.
+// Each source is expected to be in chronological order already, like the files written by a Logger.
+//
+// Entries with the same time are returned in the order of their sources. Entries without a time, and
+// the ones that couldn't be parsed, keep their place in their source: they're returned right after
+// the entry that comes before them.
+type MergeScanner struct {
+	sources []*mergeSource
+	queue   mergeQueue
+	started bool
+	current *mergeSource
+	err     error
+}
+
+type mergeSource struct {
+	MergeSource
+	index int
+	entry Entry
+	err   error
+	text  string
+	// sortTime is the time used to order the current entry. It's the time of the last entry with
+	// one, so entries without one stay where they are. Until there is one, timed is false.
+	sortTime time.Time
+	timed    bool
+}
+
+// NewMergeScanner returns a MergeScanner that merges the given sources.
+func NewMergeScanner(sources ...MergeSource) *MergeScanner {
+	s := &MergeScanner{sources: make([]*mergeSource, len(sources))}
+	for i, source := range sources {
+		s.sources[i] = &mergeSource{MergeSource: source, index: i}
+	}
+	return s
+}
+
+// Scan advances to the next entry, which is then available through Entry. It returns false once
+// all the sources are done, or reading one of them fails.
+func (s *MergeScanner) Scan() bool {
+	if s.err != nil {
+		return false
+	}
+
+	if !s.started {
+		s.started = true
+		for _, source := range s.sources {
+			if !s.advance(source) {
+				return false
+			}
+		}
+	} else if s.current != nil {
+		if !s.advance(s.current) {
+			return false
+		}
+	}
+
+	if len(s.queue) == 0 {
+		s.current = nil
+		return false
+	}
+	s.current = heap.Pop(&s.queue).(*mergeSource)
+	return true
+}
+
+// advance reads the next entry of source, and queues it. It returns false if reading failed.
+func (s *MergeScanner) advance(source *mergeSource) bool {
+	if !source.Scanner.Scan() {
+		s.err = source.Scanner.Err()
+		return s.err == nil
+	}
+
+	source.entry, source.err = source.Scanner.Entry()
+	source.text = source.Scanner.Text()
+	if source.err == nil && !source.entry.Time.IsZero() {
+		source.sortTime, source.timed = source.entry.Time, true
+	}
+	heap.Push(&s.queue, source)
+	return true
+}
+
+// Entry returns the most recent entry read by Scan, along with the error its source returned for it.
+func (s *MergeScanner) Entry() (Entry, error) {
+	if s.current == nil {
+		return Entry{}, nil
+	}
+	return s.current.entry, s.current.err
+}
+
+// Text returns the raw text of the most recent entry read by Scan.
+func (s *MergeScanner) Text() string {
+	if s.current == nil {
+		return ""
+	}
+	return s.current.text
+}
+
+// Source returns the name of the source of the most recent entry read by Scan.
+func (s *MergeScanner) Source() string {
+	if s.current == nil {
+		return ""
+	}
+	return s.current.Name
+}
+
+// Err returns the first error encountered while reading any of the sources, other than io.EOF.
+func (s *MergeScanner) Err() error {
+	return s.err
+}
+
+// mergeQueue is a heap of the sources with an entry waiting, ordered by the time of that entry.
+type mergeQueue []*mergeSource
+
+func (q mergeQueue) Len() int { return len(q) }
+
+func (q mergeQueue) Less(i, j int) bool {
+	if q[i].timed != q[j].timed {
+		return !q[i].timed
+	}
+	if !q[i].sortTime.Equal(q[j].sortTime) {
+		return q[i].sortTime.Before(q[j].sortTime)
+	}
+	return q[i].index < q[j].index
+}
+
+func (q mergeQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *mergeQueue) Push(x any) { *q = append(*q, x.(*mergeSource)) }
+
+func (q *mergeQueue) Pop() any {
+	old := *q
+	source := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return source
+}
@@ -0,0 +1,61 @@
+package jaglogger
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeScanner(t *testing.T) {
+	errLog := "level=error time=2022-07-03T22:05:02Z msg=e1\n" +
+		"level=error time=2022-07-03T22:05:04Z msg=e2\n" +
+		"not logfmt \"\n" +
+		"level=error time=2022-07-03T22:05:06Z msg=e3\n"
+	outLog := "garbage before \"\n" +
+		"level=info time=2022-07-03T22:05:01Z msg=o1\n" +
+		"level=info time=2022-07-03T22:05:04Z msg=o2\n" +
+		"level=info msg=o3\n" +
+		"level=info time=2022-07-03T22:05:07Z msg=o4\n"
+
+	s := NewMergeScanner(
+		MergeSource{Name: "err.log", Scanner: NewLogfmtScanner(strings.NewReader(errLog))},
+		MergeSource{Name: "out.log", Scanner: NewLogfmtScanner(strings.NewReader(outLog))},
+	)
+
+	var got []string
+	for s.Scan() {
+		e, err := s.Entry()
+		if err != nil {
+			got = append(got, s.Source()+" "+s.Text())
+			continue
+		}
+		got = append(got, s.Source()+" "+e.Message)
+	}
+	assert.NoError(t, s.Err())
+	assert.Equal(t, []string{
+		"out.log garbage before \"",
+		"out.log o1",
+		"err.log e1",
+		// Ties go to the first source
+		"err.log e2",
+		"err.log not logfmt \"",
+		"out.log o2",
+		"out.log o3",
+		"err.log e3",
+		"out.log o4",
+	}, got)
+}
+
+func TestMergeScanner_Err(t *testing.T) {
+	readErr := errors.New("read failed")
+	s := NewMergeScanner(
+		MergeSource{Name: "ok", Scanner: NewLogfmtScanner(strings.NewReader("level=info msg=test\n"))},
+		MergeSource{Name: "broken", Scanner: NewLogfmtScanner(iotest.ErrReader(readErr))},
+	)
+
+	assert.False(t, s.Scan())
+	assert.ErrorIs(t, s.Err(), readErr)
+}
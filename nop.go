@@ -0,0 +1,24 @@
+package jaglogger
+
+// Nop returns a Logger that discards everything logged to it. It's meant as a default for code that
+// accepts an optional Logger. Its methods do nothing, but the arguments passed to them are still
+// evaluated by the caller. Recover still does what its options say, which means it panics again by
+// default.
+func Nop() Logger {
+	return nopLogger{}
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Critical(...any)          {}
+func (nopLogger) Criticalf(string, ...any) {}
+func (nopLogger) Error(...any)             {}
+func (nopLogger) Errorf(string, ...any)    {}
+func (nopLogger) Warning(...any)           {}
+func (nopLogger) Warningf(string, ...any)  {}
+func (nopLogger) Notice(...any)            {}
+func (nopLogger) Noticef(string, ...any)   {}
+func (nopLogger) Info(...any)              {}
+func (nopLogger) Infof(string, ...any)     {}
+func (nopLogger) Debug(...any)             {}
+func (nopLogger) Debugf(string, ...any)    {}
@@ -0,0 +1,90 @@
+package jaglogger
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNop(t *testing.T) {
+	l := Nop()
+	assert.Equal(t, l, AddCallerSkip(With(l, "a", 1), 1))
+	assert.Equal(t, l, Every(l, 2))
+	assert.Equal(t, l, V(l, 1))
+	assert.NoError(t, Close(l))
+
+	allocs := testing.AllocsPerRun(100, func() {
+		l.Info("test", 1)
+		l.Errorf("test %d", 1)
+		l.Debug("test")
+	})
+	assert.Zero(t, allocs)
+
+	n, err := io.WriteString(Writer(l, LogLevelInfo), "test\n")
+	assert.Equal(t, 5, n)
+	assert.NoError(t, err)
+	StdLogger(l, LogLevelInfo).Print("test")
+
+	assert.NotPanics(t, func() {
+		defer Recover(l, RecoverSwallowOpt())
+		panic("test")
+	})
+	assert.PanicsWithValue(t, "test", func() {
+		defer Recover(l)
+		panic("test")
+	})
+}
+
+func TestDebugCompiled(t *testing.T) {
+	loggerOutput := new(bytes.Buffer)
+	l := NewLogger(LogLevelDebug, SetDebugLoggerOpt(Config{Outputs: []io.Writer{loggerOutput}, Flags: log.Lmsgprefix}))
+
+	l.Debug("test")
+	l.Debugf("test %d", 1)
+
+	if DebugCompiled {
+		assert.Equal(t, "[DEBUG]test\n[DEBUG]test 1\n", loggerOutput.String())
+		return
+	}
+	assert.Empty(t, loggerOutput.String())
+	// A call through the Logger interface still allocates its arguments, but a guarded one is removed
+	allocs := testing.AllocsPerRun(100, func() {
+		if DebugCompiled {
+			l.Debugf("test %d", 1)
+		}
+	})
+	assert.Zero(t, allocs)
+}
+
+func BenchmarkNop(b *testing.B) {
+	l := Nop()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l.Infof("test %s", "value")
+	}
+}
+
+// Run with -tags jaglogger_nodebug to see the cost of a Debug call that's compiled out. Unguarded,
+// the call is made through the Logger interface, which allocates its arguments. Guarded by
+// DebugCompiled, it's removed by the compiler.
+func Benchmark_logger_Debug(b *testing.B) {
+	l := NewLogger(LogLevelDebug, SetDebugLoggerOpt(Config{Outputs: []io.Writer{io.Discard}, Caller: CallerDisabled}))
+
+	b.Run("Unguarded", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.Debugf("test %s", "value")
+		}
+	})
+	b.Run("Guarded", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if DebugCompiled {
+				l.Debugf("test %s", "value")
+			}
+		}
+	})
+}
@@ -1,12 +1,35 @@
 package jaglogger
 
-import "io"
+import (
+	"io"
+	"strings"
+	"time"
+)
 
 // Config holds the data that will be used to build the logger of a specific log level.
 type Config struct {
 	Outputs []io.Writer
 	Prefix  string
 	Flags   int
+	// Caller determines how the caller is reported. When set, it takes the place of the
+	// log.Llongfile and log.Lshortfile flags.
+	Caller CallerFormat
+	Format Format
+	// StackTrace adds the stack trace of the goroutine at the call site to every entry. It's written
+	// as an indented block after the entry in the text formats, and as an array of frames in the
+	// structured formats.
+	StackTrace bool
+	// Sampling limits how many entries with the same message are written per interval. When it's
+	// nil, the Sampling set with SetDefaultSamplingOpt is used, and &Sampling{} disables it.
+	Sampling *Sampling
+	// RateLimit caps the rate at which entries are written. RateLimitOutput can be used to limit a
+	// single output instead.
+	RateLimit RateLimit
+	// Dedup collapses consecutive entries with the same message and fields that are logged within
+	// the given window into the first of them, followed by an entry saying how many times it was
+	// repeated. That entry is written when a different entry is logged, when the window elapses, or
+	// when the logger is closed.
+	Dedup time.Duration
 }
 
 // Option is a function type that allows modifications of settings for the logger
@@ -18,6 +41,21 @@ type settings struct {
 	DefaultErrOutputs    []io.Writer
 	DefaultNonErrOutputs []io.Writer
 	DefaultFlags         int
+	DefaultCaller        CallerFormat
+	CallerTrimPrefixes   []string
+	TimeFormat           string
+	TimeLocation         *time.Location
+	Clock                func() time.Time
+	DefaultFormat        Format
+	DefaultSampling      Sampling
+	DefaultDedup         time.Duration
+	EntryHooks           []func(Entry)
+	Redaction            *redaction
+	Sanitize             bool
+	Multiline            MultilinePolicy
+	Verbosity            *Verbosity
+	Color                *bool
+	Sink                 Sink
 }
 
 // SetCriticalLoggerOpt sets the logger configuration for the "Critical" log level
@@ -74,3 +112,162 @@ func SetDefaultFlagsOpt(flag int) Option {
 		s.DefaultFlags = flag
 	}
 }
+
+// SetDefaultFormatOpt sets the Format used by log levels that don't set one in their Config.
+func SetDefaultFormatOpt(format Format) Option {
+	return func(s *settings) {
+		s.DefaultFormat = format
+	}
+}
+
+// SetDefaultSamplingOpt sets the Sampling used by log levels that don't set one in their Config.
+func SetDefaultSamplingOpt(sampling Sampling) Option {
+	return func(s *settings) {
+		s.DefaultSampling = sampling
+	}
+}
+
+// SetDefaultDedupOpt sets the Dedup window used by log levels that don't set one in their Config.
+func SetDefaultDedupOpt(window time.Duration) Option {
+	return func(s *settings) {
+		s.DefaultDedup = window
+	}
+}
+
+// SetDefaultCallerOpt sets the CallerFormat used by log levels that don't set one in their Config.
+func SetDefaultCallerOpt(format CallerFormat) Option {
+	return func(s *settings) {
+		s.DefaultCaller = format
+	}
+}
+
+// SetCallerTrimPrefixesOpt sets the prefixes that are removed from the caller's file path when using
+// CallerTrimmedFile. Only the first matching prefix is removed.
+func SetCallerTrimPrefixesOpt(prefixes []string) Option {
+	return func(s *settings) {
+		s.CallerTrimPrefixes = prefixes
+	}
+}
+
+// Special values for SetTimeFormatOpt that write the time as the number of seconds, milliseconds,
+// microseconds or nanoseconds since the Unix epoch.
+const (
+	TimeFormatUnix      = "unix"
+	TimeFormatUnixMilli = "unixmilli"
+	TimeFormatUnixMicro = "unixmicro"
+	TimeFormatUnixNano  = "unixnano"
+)
+
+// SetTimeFormatOpt sets the layout, as used by time.Time.Format, of the timestamps. It may also be
+// one of the TimeFormatUnix constants. The timestamp is only written for log levels that have at
+// least one of the log.Ldate, log.Ltime or log.Lmicroseconds flags set, and the layout replaces the
+// formatting those flags would have done.
+func SetTimeFormatOpt(layout string) Option {
+	return func(s *settings) {
+		s.TimeFormat = layout
+	}
+}
+
+// SetTimeLocationOpt sets the time zone the timestamps are written in, e.g. time.UTC.
+// Log levels with the log.LUTC flag set will still be written in UTC.
+func SetTimeLocationOpt(loc *time.Location) Option {
+	return func(s *settings) {
+		s.TimeLocation = loc
+	}
+}
+
+// SetClockOpt sets the function used to get the time of each log entry. This is mostly useful
+// for getting consistent timestamps in tests.
+func SetClockOpt(clock func() time.Time) Option {
+	return func(s *settings) {
+		s.Clock = clock
+	}
+}
+
+// SetEntryHookOpt adds a function that's called with every entry at or above the minimum log level,
+// in addition to writing it to the outputs. It's called before the entry is formatted, and may be
+// called concurrently.
+func SetEntryHookOpt(hook func(Entry)) Option {
+	return func(s *settings) {
+		s.EntryHooks = append(s.EntryHooks, hook)
+	}
+}
+
+// Sink receives the entries of a logger along with its outputs. It's meant for writing them to a
+// test, like jaglogtest.NewTestLogger does.
+type Sink interface {
+	// Helper is called by each function that a logged entry goes through, including the logging
+	// methods of Logger, so that testing.TB.Helper can leave them out of the reported file and line.
+	Helper()
+	// WriteEntry is called with every entry and its formatted form, without the trailing newline. That
+	// includes the entries reporting what Sampling, RateLimit and Dedup left out.
+	WriteEntry(e Entry, line []byte)
+}
+
+// SetSinkOpt makes the logger write every entry at or above its minimum level to sink, formatted
+// the way the Config of its log level formats it, along with its outputs.
+func SetSinkOpt(sink Sink) Option {
+	return func(s *settings) {
+		s.Sink = sink
+	}
+}
+
+// SetColorOpt turns the colors of the FormatConsole outputs on or off, instead of deciding from
+// whether they're terminals and from the FORCE_COLOR and NO_COLOR environment variables.
+func SetColorOpt(enabled bool) Option {
+	return func(s *settings) {
+		s.Color = &enabled
+	}
+}
+
+// SetRedactKeysOpt replaces the values of the fields with one of the given keys, like "password"
+// or "authorization", using strategy. Keys match case-insensitively, and also match the last part
+// of a dotted key, like the keys of the fields of an Object. DefaultRedactKeys holds common ones.
+func SetRedactKeysOpt(strategy RedactStrategy, keys ...string) Option {
+	return func(s *settings) {
+		if s.Redaction == nil {
+			s.Redaction = &redaction{}
+		}
+		if s.Redaction.keys == nil {
+			s.Redaction.keys = make(map[string]RedactStrategy, len(keys))
+		}
+		for _, key := range keys {
+			s.Redaction.keys[strings.ToLower(key)] = strategy
+		}
+	}
+}
+
+// SetRedactPatternsOpt replaces the text matched by the patterns in messages and in the values of
+// fields that are strings, errors or fmt.Stringers, using strategy. DefaultRedactPatterns holds
+// patterns for bearer tokens, AWS keys, card numbers and email addresses.
+func SetRedactPatternsOpt(strategy RedactStrategy, patterns ...RedactPattern) Option {
+	return func(s *settings) {
+		if s.Redaction == nil {
+			s.Redaction = &redaction{}
+		}
+		for _, p := range patterns {
+			s.Redaction.patterns = append(s.Redaction.patterns, strategyPattern{RedactPattern: p, strategy: strategy})
+		}
+	}
+}
+
+// SetSanitizeOpt makes the FormatText and FormatConsole formats escape the characters of messages and
+// field keys that could forge entries or mess with a terminal: carriage returns, ANSI escape codes
+// and other control characters, Unicode line separators and bidirectional overrides, and invalid
+// UTF-8. Newlines in messages are handled according to multiline, and always escaped in keys. Field
+// values are quoted and escaped by these formats whether this is set or not, and the structured
+// formats escape everything already.
+func SetSanitizeOpt(multiline MultilinePolicy) Option {
+	return func(s *settings) {
+		s.Sanitize = true
+		s.Multiline = multiline
+	}
+}
+
+// SetVerbosityOpt sets the Verbosity that decides which calls to V are enabled. The same
+// Verbosity can be given to several loggers, and changed while they're in use.
+func SetVerbosityOpt(v *Verbosity) Option {
+	return func(s *settings) {
+		s.Verbosity = v
+	}
+}
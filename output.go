@@ -0,0 +1,385 @@
+package jaglogger
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Entry is a single log entry.
+type Entry struct {
+	Level   LogLevel
+	Time    time.Time
+	Caller  Caller
+	Message string
+	Fields  []Field
+	// Stack is the stack trace of the call site, when Config.StackTrace is set
+	Stack Stack
+}
+
+// levelOutput formats and writes the entries of a single log level. With FormatText, the format matches
+// that of a log.Logger with the same prefix and flags, except for how the caller is reported.
+type levelOutput struct {
+	mu           sync.Mutex
+	outputs      []io.Writer
+	prefix       string
+	flags        int
+	caller       CallerFormat
+	trimPrefixes []string
+	timeFormat   string
+	location     *time.Location
+	format       Format
+	stackTrace   bool
+	hooks        []func(Entry)
+	redact       *redaction
+	sanitize     bool
+	multiline    MultilinePolicy
+	sampler      *sampler
+	limiter      *rateLimiter
+	deduper      *deduper
+	// sink is the Sink set with SetSinkOpt, or nil
+	sink Sink
+	// colors holds whether the output at the same index gets colored entries
+	colors   []bool
+	buf      []byte
+	colorBuf []byte
+}
+
+// enabled reports whether there is anywhere to write the entries to.
+func (lo *levelOutput) enabled() bool {
+	return len(lo.outputs) > 0 || len(lo.hooks) > 0 || lo.sink != nil
+}
+
+// write passes e to the hooks, then formats it and writes it to the sink and the outputs.
+func (lo *levelOutput) write(e *Entry) {
+	if lo.sink != nil {
+		lo.sink.Helper()
+	}
+	lo.redact.entry(e)
+	for _, hook := range lo.hooks {
+		hook(*e)
+	}
+	if lo.sink != nil {
+		lo.sink.WriteEntry(*e, bytes.TrimSuffix(lo.appendEntry(nil, e, false), []byte("\n")))
+	}
+	if len(lo.outputs) == 0 {
+		return
+	}
+
+	lo.mu.Lock()
+	defer lo.mu.Unlock()
+
+	lo.buf, lo.colorBuf = lo.buf[:0], lo.colorBuf[:0]
+	for i, w := range lo.outputs {
+		if rl, ok := w.(*rateLimitedOutput); ok && !lo.allowOutput(rl, i, e) {
+			continue
+		}
+		if i < len(lo.colors) && lo.colors[i] {
+			if len(lo.colorBuf) == 0 {
+				lo.colorBuf = lo.appendEntry(lo.colorBuf, e, true)
+			}
+			w.Write(lo.colorBuf)
+			continue
+		}
+
+		if len(lo.buf) == 0 {
+			lo.buf = lo.appendEntry(lo.buf, e, false)
+		}
+		w.Write(lo.buf)
+	}
+}
+
+// allowOutput reports whether e can be written to the output at index i, which is rl. When entries
+// dropped before e haven't been reported yet, it writes the entry reporting them first. lo.mu must
+// be held.
+func (lo *levelOutput) allowOutput(rl *rateLimitedOutput, i int, e *Entry) bool {
+	level, color := e.Level, i < len(lo.colors) && lo.colors[i]
+	ok, dropped := rl.limiter.allow(e.Time, func(dropped int, last time.Time) {
+		lo.mu.Lock()
+		defer lo.mu.Unlock()
+		rl.Write(lo.appendEntry(nil, rateLimitEntry(level, last, dropped), color))
+	})
+	if dropped > 0 {
+		rl.Write(lo.appendEntry(nil, rateLimitEntry(e.Level, e.Time, dropped), color))
+	}
+	return ok
+}
+
+// appendEntry appends e to buf, formatted according to lo.format.
+func (lo *levelOutput) appendEntry(buf []byte, e *Entry, color bool) []byte {
+	switch lo.format {
+	case FormatConsole:
+		return lo.appendConsole(buf, e, color)
+	case FormatLogfmt:
+		return lo.appendLogfmt(buf, e)
+	case FormatJSON:
+		return lo.appendJSON(buf, e)
+	default:
+		return lo.appendText(buf, e)
+	}
+}
+
+// appendText appends e to buf in the format of the log package, followed by its fields.
+func (lo *levelOutput) appendText(buf []byte, e *Entry) []byte {
+	buf = lo.appendHeader(buf, e.Time, e.Caller)
+	msg := e.Message
+	if len(e.Fields) > 0 || lo.sanitize {
+		msg = strings.TrimSuffix(msg, "\n")
+	}
+	buf = lo.appendMessage(buf, msg)
+	buf = lo.appendFields(buf, e.Fields)
+	if len(buf) == 0 || buf[len(buf)-1] != '\n' {
+		buf = append(buf, '\n')
+	}
+	return appendStackBlocks(buf, e)
+}
+
+// appendMessage appends msg to buf, sanitized if SetSanitizeOpt is set.
+func (lo *levelOutput) appendMessage(buf []byte, msg string) []byte {
+	if lo.sanitize {
+		return appendSanitized(buf, msg, lo.multiline)
+	}
+	return append(buf, msg...)
+}
+
+// appendKey appends the key of a field to buf, sanitized if SetSanitizeOpt is set.
+func (lo *levelOutput) appendKey(buf []byte, key string) []byte {
+	if lo.sanitize {
+		return appendSanitized(buf, key, MultilineEscape)
+	}
+	return append(buf, key...)
+}
+
+// hasStackBlocks reports whether appendStackBlocks appends anything for e.
+func hasStackBlocks(e *Entry) bool {
+	if len(e.Stack) > 0 {
+		return true
+	}
+	for _, f := range e.Fields {
+		if stack, ok := f.Value.(Stack); ok && len(stack) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// appendStackBlocks appends the stack of e, followed by the fields whose value is a Stack, as
+// indented blocks for the text formats.
+func appendStackBlocks(buf []byte, e *Entry) []byte {
+	if len(e.Stack) > 0 {
+		buf = e.Stack.appendBlock(buf, "")
+	}
+	for _, f := range e.Fields {
+		if stack, ok := f.Value.(Stack); ok && len(stack) > 0 {
+			buf = stack.appendBlock(buf, f.Key)
+		}
+	}
+	return buf
+}
+
+// appendHeader appends everything that comes before the message to buf.
+func (lo *levelOutput) appendHeader(buf []byte, t time.Time, c Caller) []byte {
+	if lo.flags&log.Lmsgprefix == 0 {
+		buf = append(buf, lo.prefix...)
+	}
+
+	if lo.hasTime() {
+		buf = lo.appendTime(buf, t)
+		buf = append(buf, ' ')
+	}
+
+	// Entries without a caller, like the ones reporting what Sampling, RateLimit and Dedup left out,
+	// are written without one
+	if lo.caller&CallerDisabled == 0 && c.Defined {
+		buf = lo.appendCaller(buf, c)
+	}
+
+	if lo.flags&log.Lmsgprefix != 0 {
+		buf = append(buf, lo.prefix...)
+	}
+	return buf
+}
+
+// hasTime reports whether the timestamp is written.
+func (lo *levelOutput) hasTime() bool {
+	return lo.flags&(log.Ldate|log.Ltime|log.Lmicroseconds) != 0
+}
+
+// localTime returns t in the time zone set for lo.
+func (lo *levelOutput) localTime(t time.Time) time.Time {
+	if lo.flags&log.LUTC != 0 {
+		return t.UTC()
+	} else if lo.location != nil {
+		return t.In(lo.location)
+	}
+	return t
+}
+
+// appendTime appends t to buf, in the time zone and format set for lo.
+func (lo *levelOutput) appendTime(buf []byte, t time.Time) []byte {
+	if lo.timeFormat != "" {
+		return appendTime(buf, lo.localTime(t), lo.timeFormat)
+	}
+	return appendFlagsTime(buf, lo.localTime(t), lo.flags)
+}
+
+// appendStructuredTime appends t to buf for the structured formats. Unless a time format is set,
+// that's RFC 3339, with microseconds when the log.Lmicroseconds flag is set.
+func (lo *levelOutput) appendStructuredTime(buf []byte, t time.Time) []byte {
+	if lo.timeFormat != "" {
+		return lo.appendTime(buf, t)
+	}
+	if lo.flags&log.Lmicroseconds != 0 {
+		return lo.localTime(t).AppendFormat(buf, "2006-01-02T15:04:05.000000Z07:00")
+	}
+	return lo.localTime(t).AppendFormat(buf, time.RFC3339)
+}
+
+// appendFlagsTime appends t to buf, formatted according to the log.Ldate, log.Ltime and
+// log.Lmicroseconds flags, the same way the log package does.
+func appendFlagsTime(buf []byte, t time.Time, flags int) []byte {
+	if flags&log.Ldate != 0 {
+		year, month, day := t.Date()
+		buf = appendInt(buf, year, 4)
+		buf = append(buf, '/')
+		buf = appendInt(buf, int(month), 2)
+		buf = append(buf, '/')
+		buf = appendInt(buf, day, 2)
+	}
+	if flags&(log.Ltime|log.Lmicroseconds) != 0 {
+		if flags&log.Ldate != 0 {
+			buf = append(buf, ' ')
+		}
+		hour, min, sec := t.Clock()
+		buf = appendInt(buf, hour, 2)
+		buf = append(buf, ':')
+		buf = appendInt(buf, min, 2)
+		buf = append(buf, ':')
+		buf = appendInt(buf, sec, 2)
+		if flags&log.Lmicroseconds != 0 {
+			buf = append(buf, '.')
+			buf = appendInt(buf, t.Nanosecond()/1e3, 6)
+		}
+	}
+	return buf
+}
+
+// appendTime appends t to buf, formatted with the given time layout or one of the
+// TimeFormatUnix constants.
+func appendTime(buf []byte, t time.Time, format string) []byte {
+	switch format {
+	case TimeFormatUnix:
+		return strconv.AppendInt(buf, t.Unix(), 10)
+	case TimeFormatUnixMilli:
+		return strconv.AppendInt(buf, t.UnixMilli(), 10)
+	case TimeFormatUnixMicro:
+		return strconv.AppendInt(buf, t.UnixMicro(), 10)
+	case TimeFormatUnixNano:
+		return strconv.AppendInt(buf, t.UnixNano(), 10)
+	default:
+		return t.AppendFormat(buf, format)
+	}
+}
+
+// appendCaller appends the caller, formatted according to lo.caller, followed by ": " to buf.
+func (lo *levelOutput) appendCaller(buf []byte, c Caller) []byte {
+	file, line, function := lo.callerParts(c)
+	if lo.caller&callerFileFormats != 0 {
+		buf = append(buf, file...)
+		buf = append(buf, ':')
+		buf = strconv.AppendInt(buf, int64(line), 10)
+		if lo.caller&CallerFunction != 0 {
+			buf = append(buf, ' ')
+		}
+	}
+	if lo.caller&CallerFunction != 0 {
+		buf = append(buf, function...)
+	}
+	return append(buf, ": "...)
+}
+
+// callerParts returns the file path, formatted according to lo.caller, line number and function
+// of the caller. "???" and 0 are used when the caller is unknown.
+func (lo *levelOutput) callerParts(c Caller) (file string, line int, function string) {
+	if !c.Defined {
+		return "???", 0, "???"
+	}
+	return callerPath(c, lo.caller, lo.trimPrefixes), c.Line, c.Function
+}
+
+// appendInt appends i to buf as a decimal zero-padded to the given width.
+func appendInt(buf []byte, i int, width int) []byte {
+	var b [20]byte
+	bp := len(b) - 1
+	for i >= 10 || width > 1 {
+		width--
+		q := i / 10
+		b[bp] = byte('0' + i - q*10)
+		bp--
+		i = q
+	}
+	b[bp] = byte('0' + i)
+	return append(buf, b[bp:]...)
+}
+
+// EntryWriter writes entries that have already been created, such as the ones read by a Scanner,
+// the same way a Logger created with the same options would.
+type EntryWriter struct {
+	outputs map[LogLevel]*levelOutput
+}
+
+// NewEntryWriter returns an EntryWriter that's configured the same way NewLogger configures a Logger.
+func NewEntryWriter(minLevel LogLevel, opts ...Option) *EntryWriter {
+	outputs, _ := newLevelOutputs(minLevel, opts)
+	return &EntryWriter{outputs: outputs}
+}
+
+// Write writes e to the outputs of its log level. Entries with an invalid log level are written to
+// the outputs of LogLevelInfo.
+func (w *EntryWriter) Write(e Entry) {
+	lo, ok := w.outputs[e.Level]
+	if !ok {
+		lo = w.outputs[LogLevelInfo]
+	}
+	if lo.enabled() {
+		lo.write(&e)
+	}
+}
+
+// flushPending writes the entries that report the entries of the given level that were left out, if
+// there are any.
+func (lo *levelOutput) flushPending(level LogLevel) {
+	if lo.deduper != nil {
+		lo.deduper.flush(lo, level)
+	}
+	if lo.sampler != nil {
+		lo.sampler.flush(lo, level)
+	}
+	if lo.limiter != nil {
+		lo.limiter.flush()
+	}
+	for _, w := range lo.outputs {
+		if rl, ok := w.(*rateLimitedOutput); ok {
+			rl.limiter.flush()
+		}
+	}
+}
+
+// flush flushes the outputs that have a Flush or Sync method. Errors are ignored, since e.g. syncing
+// a terminal fails on some platforms.
+func (lo *levelOutput) flush() {
+	lo.mu.Lock()
+	defer lo.mu.Unlock()
+	for _, w := range lo.outputs {
+		switch w := w.(type) {
+		case interface{ Flush() error }:
+			w.Flush()
+		case interface{ Sync() error }:
+			w.Sync()
+		}
+	}
+}
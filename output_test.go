@@ -0,0 +1,189 @@
+package jaglogger
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_logger_TimeFormat(t *testing.T) {
+	type args struct {
+		opts  []Option
+		flags int
+	}
+
+	est := time.FixedZone("EST", -5*60*60)
+	clock := func() time.Time {
+		return time.Date(2022, time.July, 3, 22, 5, 3, 123456789, est)
+	}
+
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "Flags",
+			args: args{flags: log.Ldate | log.Ltime | log.Lmsgprefix},
+			want: "2022/07/03 22:05:03 [INFO]test\n",
+		},
+		{
+			name: "Flags Microseconds",
+			args: args{flags: log.Ltime | log.Lmicroseconds | log.Lmsgprefix},
+			want: "22:05:03.123456 [INFO]test\n",
+		},
+		{
+			name: "Flags UTC",
+			args: args{flags: log.Ldate | log.Ltime | log.LUTC | log.Lmsgprefix},
+			want: "2022/07/04 03:05:03 [INFO]test\n",
+		},
+		{
+			name: "Layout",
+			args: args{opts: []Option{SetTimeFormatOpt(time.RFC3339Nano)}, flags: log.Ldate | log.Lmsgprefix},
+			want: "2022-07-03T22:05:03.123456789-05:00 [INFO]test\n",
+		},
+		{
+			name: "Layout Without Time Flags",
+			args: args{opts: []Option{SetTimeFormatOpt(time.RFC3339Nano)}, flags: log.Lmsgprefix},
+			want: "[INFO]test\n",
+		},
+		{
+			name: "Unix Milliseconds",
+			args: args{opts: []Option{SetTimeFormatOpt(TimeFormatUnixMilli)}, flags: log.Ldate | log.Lmsgprefix},
+			want: "1656903903123 [INFO]test\n",
+		},
+		{
+			name: "Unix Seconds",
+			args: args{opts: []Option{SetTimeFormatOpt(TimeFormatUnix)}, flags: log.Ldate | log.Lmsgprefix},
+			want: "1656903903 [INFO]test\n",
+		},
+		{
+			name: "Location",
+			args: args{opts: []Option{SetTimeFormatOpt(time.RFC3339), SetTimeLocationOpt(time.UTC)}, flags: log.Ldate | log.Lmsgprefix},
+			want: "2022-07-04T03:05:03Z [INFO]test\n",
+		},
+		{
+			name: "Location With Flags",
+			args: args{opts: []Option{SetTimeLocationOpt(time.UTC)}, flags: log.Ldate | log.Ltime | log.Lmsgprefix},
+			want: "2022/07/04 03:05:03 [INFO]test\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loggerOutput := new(bytes.Buffer)
+			opts := append([]Option{
+				SetClockOpt(clock),
+				SetInfoLoggerOpt(Config{Outputs: []io.Writer{loggerOutput}, Flags: tt.args.flags}),
+			}, tt.args.opts...)
+			l := NewLogger(LogLevelInfo, opts...)
+
+			l.Info("test")
+			got := loggerOutput.String()
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEntryWriter(t *testing.T) {
+	output := new(bytes.Buffer)
+	w := NewEntryWriter(
+		LogLevelInfo,
+		SetDefaultFlagsOpt(log.Ldate|log.Ltime|log.LUTC|log.Lshortfile),
+		SetDefaultErrorOutputsOpt([]io.Writer{output}),
+		SetDefaultNonErrorOutputOpt([]io.Writer{output}),
+	)
+
+	e := Entry{
+		Time:    time.Date(2022, time.July, 3, 22, 5, 3, 0, time.UTC),
+		Caller:  Caller{File: "/path/to/main.go", Line: 8, Defined: true},
+		Message: "test",
+		Fields:  []Field{{Key: "a", Value: 1}},
+	}
+	for _, level := range []LogLevel{LogLevelError, LogLevelDebug, 0} {
+		e.Level = level
+		w.Write(e)
+	}
+
+	want := "[ERROR]2022/07/03 22:05:03 main.go:8: test a=1\n" +
+		"[INFO]2022/07/03 22:05:03 main.go:8: test a=1\n"
+	assert.Equal(t, want, output.String())
+}
+
+func TestSetEntryHookOpt(t *testing.T) {
+	var got []Entry
+	loggerOutput := new(bytes.Buffer)
+	l := NewLogger(
+		LogLevelInfo,
+		SetDefaultNonErrorOutputOpt([]io.Writer{loggerOutput}),
+		SetDefaultFlagsOpt(log.Lmsgprefix),
+		SetEntryHookOpt(func(e Entry) { got = append(got, e) }),
+	)
+
+	l.Debug("ignored")
+	With(l, "a", 1).Info("test")
+
+	assert.Equal(t, "[INFO]test a=1\n", loggerOutput.String())
+	if assert.Len(t, got, 1) {
+		assert.Equal(t, LogLevelInfo, got[0].Level)
+		assert.Equal(t, "test", got[0].Message)
+		assert.Equal(t, []Field{{Key: "a", Value: 1}}, got[0].Fields)
+	}
+}
+
+// recordingSink records the lines written to it, and how many times Helper was called.
+type recordingSink struct {
+	helpers int
+	lines   []string
+}
+
+func (s *recordingSink) Helper() {
+	s.helpers++
+}
+
+func (s *recordingSink) WriteEntry(e Entry, line []byte) {
+	s.lines = append(s.lines, string(line))
+}
+
+func TestSetSinkOpt(t *testing.T) {
+	sink := &recordingSink{}
+	l := NewLogger(
+		LogLevelInfo,
+		SetDefaultNonErrorOutputOpt(nil),
+		SetDefaultFlagsOpt(log.Lmsgprefix),
+		SetSinkOpt(sink),
+	)
+
+	l.Debug("ignored")
+	With(l, "a", 1).Info("test")
+
+	assert.Equal(t, []string{"[INFO]test a=1"}, sink.lines)
+	assert.NotZero(t, sink.helpers)
+}
+
+func Test_logger_FormatOverride(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		want   string
+	}{
+		{name: "Default", want: `{"level":"info","msg":"test"}` + "\n"},
+		{name: "Text", format: FormatText, want: "[INFO]test\n"},
+		{name: "Logfmt", format: FormatLogfmt, want: "level=info msg=test\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loggerOutput := new(bytes.Buffer)
+			l := NewLogger(
+				LogLevelInfo,
+				SetDefaultFormatOpt(FormatJSON),
+				SetInfoLoggerOpt(Config{Outputs: []io.Writer{loggerOutput}, Flags: log.Lmsgprefix, Caller: CallerDisabled, Format: tt.format}),
+			)
+			l.Info("test")
+			assert.Equal(t, tt.want, loggerOutput.String())
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package jaglogger
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Scanner reads log entries from a stream. Like bufio.Scanner, it's used by calling Scan until it
+// returns false:
+//
+//	for s.Scan() {
+//		entry, err := s.Entry()
+//		if err != nil {
+//			// the entry couldn't be parsed, s.Text() has the raw text
+//		}
+//		...
+//	}
+//	if err := s.Err(); err != nil {
+//		...
+//	}
+type Scanner interface {
+	// Scan advances to the next entry, which is then available through Entry. It returns false
+	// once there are no more entries or reading fails.
+	Scan() bool
+	// Entry returns the most recent entry read by Scan. If it couldn't be parsed, a *ParseError
+	// is returned along with whatever could be parsed.
+	Entry() (Entry, error)
+	// Text returns the raw text of the most recent entry read by Scan.
+	Text() string
+	// Err returns the first error encountered while reading, other than io.EOF.
+	Err() error
+}
+
+// ParseError describes why a log line couldn't be parsed.
+type ParseError struct {
+	// Line is the line number, starting at 1, of the line that couldn't be parsed.
+	// It's 0 when parsing a single line.
+	Line int
+	Msg  string
+}
+
+func (e *ParseError) Error() string {
+	if e.Line == 0 {
+		return "jaglogger: " + e.Msg
+	}
+	return fmt.Sprintf("jaglogger: line %d: %s", e.Line, e.Msg)
+}
+
+// parseTimestamp parses the timestamps written by the structured formats. Along with RFC 3339
+// timestamps, it accepts the number of seconds, milliseconds, microseconds or nanoseconds since the
+// Unix epoch, as written with the TimeFormatUnix constants. Which of these it is gets guessed from
+// the number of digits.
+func parseTimestamp(s string) (time.Time, bool) {
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, true
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return time.Time{}, false
+	}
+	switch {
+	case len(s) <= 10:
+		return time.Unix(n, 0), true
+	case len(s) <= 13:
+		return time.UnixMilli(n), true
+	case len(s) <= 16:
+		return time.UnixMicro(n), true
+	default:
+		return time.Unix(0, n), true
+	}
+}
+
+// maxScanLineLength is the length of the longest line the scanners can read.
+const maxScanLineLength = 1024 * 1024
+
+// LineScanner is a Scanner for formats that write each entry on a single line. Blank lines are skipped.
+type LineScanner struct {
+	s       *bufio.Scanner
+	parse   func(string) (Entry, error)
+	lineNum int
+	entry   Entry
+	err     error
+}
+
+// NewLineScanner returns a LineScanner that reads from r, and uses parse to parse each line. It can be
+// used to read formats other than the ones written by jaglogger, or to read FormatText entries one line
+// at a time with TextParser.Parse.
+func NewLineScanner(r io.Reader, parse func(line string) (Entry, error)) *LineScanner {
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, maxScanLineLength)
+	return &LineScanner{s: s, parse: parse}
+}
+
+// Scan advances to the next entry, which is then available through Entry. It returns false once
+// there are no more entries or reading fails.
+func (s *LineScanner) Scan() bool {
+	for s.s.Scan() {
+		s.lineNum++
+		if strings.TrimSpace(s.s.Text()) == "" {
+			continue
+		}
+
+		s.entry, s.err = s.parse(s.s.Text())
+		if pe, ok := s.err.(*ParseError); ok {
+			pe.Line = s.lineNum
+		}
+		return true
+	}
+	return false
+}
+
+// Entry returns the most recent entry read by Scan. If its line couldn't be parsed, a *ParseError
+// is returned along with whatever could be parsed before the problem was found.
+func (s *LineScanner) Entry() (Entry, error) {
+	return s.entry, s.err
+}
+
+// Text returns the line of the most recent entry read by Scan.
+func (s *LineScanner) Text() string {
+	return s.s.Text()
+}
+
+// Err returns the first error encountered while reading, other than io.EOF.
+func (s *LineScanner) Err() error {
+	return s.s.Err()
+}
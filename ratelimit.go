@@ -0,0 +1,163 @@
+package jaglogger
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimit caps the rate at which entries are written, using a token bucket: Burst entries can be
+// written at once, and the bucket refills at Rate entries per second. When entries are dropped, an
+// entry reporting how many is written as soon as the bucket has refilled enough for one more entry.
+// Rate limiting is disabled when Rate is 0.
+type RateLimit struct {
+	// Rate is the number of entries per second.
+	Rate float64
+	// Burst is the number of entries that can be written at once. Defaults to Rate rounded up.
+	Burst int
+}
+
+func (rl RateLimit) enabled() bool {
+	return rl.Rate > 0
+}
+
+// rateLimiter is the token bucket of a RateLimit.
+type rateLimiter struct {
+	mu      sync.Mutex
+	limit   RateLimit
+	tokens  float64
+	last    time.Time
+	dropped int
+	// lastDropped is the time of the last dropped entry
+	lastDropped time.Time
+	timer       *time.Timer
+	onDropped   func(dropped int, last time.Time)
+}
+
+func newRateLimiter(limit RateLimit) *rateLimiter {
+	if limit.Burst <= 0 {
+		limit.Burst = int(math.Ceil(limit.Rate))
+	}
+	return &rateLimiter{limit: limit, tokens: float64(limit.Burst)}
+}
+
+// allow reports whether an entry logged at now can be written, along with the number of entries
+// dropped before it that haven't been reported yet. When the entry is dropped, onDropped is called
+// with that number and the time of the last dropped entry once the bucket has refilled, unless an
+// entry is allowed before then.
+func (r *rateLimiter) allow(now time.Time, onDropped func(dropped int, last time.Time)) (bool, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.last.IsZero() && now.After(r.last) {
+		r.tokens = math.Min(r.tokens+now.Sub(r.last).Seconds()*r.limit.Rate, float64(r.limit.Burst))
+	}
+	if r.last.IsZero() || now.After(r.last) {
+		r.last = now
+	}
+
+	if r.tokens >= 1 {
+		r.tokens--
+		dropped := r.dropped
+		r.dropped = 0
+		if r.timer != nil {
+			r.timer.Stop()
+			r.timer = nil
+		}
+		return true, dropped
+	}
+
+	r.dropped++
+	r.lastDropped = now
+	r.onDropped = onDropped
+	if r.timer == nil {
+		wait := time.Duration((1 - r.tokens) / r.limit.Rate * float64(time.Second))
+		r.timer = time.AfterFunc(wait, func() {
+			r.mu.Lock()
+			dropped, last := r.dropped, r.lastDropped
+			r.dropped = 0
+			r.timer = nil
+			r.mu.Unlock()
+
+			if dropped > 0 {
+				onDropped(dropped, last)
+			}
+		})
+	}
+	return false, 0
+}
+
+// flush reports the entries dropped since the last entry that was allowed, if there are any,
+// without waiting for the bucket to refill.
+func (r *rateLimiter) flush() {
+	r.mu.Lock()
+	if r.timer != nil {
+		r.timer.Stop()
+		r.timer = nil
+	}
+	dropped, last, onDropped := r.dropped, r.lastDropped, r.onDropped
+	r.dropped = 0
+	r.mu.Unlock()
+
+	if dropped > 0 {
+		onDropped(dropped, last)
+	}
+}
+
+// rateLimitEntry returns the entry reporting that dropped entries of the given level were dropped.
+func rateLimitEntry(level LogLevel, t time.Time, dropped int) *Entry {
+	return &Entry{
+		Level:   level,
+		Time:    t,
+		Message: fmt.Sprintf("rate limit dropped %d entries", dropped),
+		Fields:  []Field{Int("dropped", dropped)},
+	}
+}
+
+// allow reports whether e is within the RateLimit of lo. When entries dropped before e haven't been
+// reported yet, it writes the entry reporting them first.
+func (lo *levelOutput) allow(e *Entry) bool {
+	level := e.Level
+	ok, dropped := lo.limiter.allow(e.Time, func(dropped int, last time.Time) {
+		lo.write(rateLimitEntry(level, last, dropped))
+	})
+	if dropped > 0 {
+		lo.write(rateLimitEntry(level, e.Time, dropped))
+	}
+	return ok
+}
+
+// RateLimitOutput returns an output that writes entries to w at the rate set by limit, so that e.g.
+// a paging service only gets a few errors per second while a file gets all of them. The limit is
+// shared by every log level that the returned writer is an output of. w is returned as it is when
+// limit is disabled.
+func RateLimitOutput(w io.Writer, limit RateLimit) io.Writer {
+	if !limit.enabled() {
+		return w
+	}
+	return &rateLimitedOutput{w: w, limiter: newRateLimiter(limit)}
+}
+
+// rateLimitedOutput is recognized by levelOutput, which asks it whether to write each entry. Writes
+// that don't come from a levelOutput aren't limited.
+type rateLimitedOutput struct {
+	w       io.Writer
+	limiter *rateLimiter
+}
+
+func (o *rateLimitedOutput) Write(p []byte) (int, error) {
+	return o.w.Write(p)
+}
+
+// Flush flushes the underlying writer, if it has a Flush or Sync method.
+func (o *rateLimitedOutput) Flush() error {
+	switch w := o.w.(type) {
+	case interface{ Flush() error }:
+		return w.Flush()
+	case interface{ Sync() error }:
+		return w.Sync()
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package jaglogger
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_rateLimiter_allow(t *testing.T) {
+	start := time.Date(2022, time.July, 3, 22, 5, 3, 0, time.UTC)
+	r := newRateLimiter(RateLimit{Rate: 2})
+	noop := func(int, time.Time) {}
+
+	var got []bool
+	var gotDropped []int
+	for _, offset := range []time.Duration{0, 0, 0, 250 * time.Millisecond, 500 * time.Millisecond, 500 * time.Millisecond, time.Minute} {
+		ok, dropped := r.allow(start.Add(offset), noop)
+		got = append(got, ok)
+		gotDropped = append(gotDropped, dropped)
+	}
+	assert.Equal(t, []bool{true, true, false, false, true, false, true}, got)
+	assert.Equal(t, []int{0, 0, 0, 0, 2, 0, 1}, gotDropped)
+}
+
+func Test_logger_RateLimit(t *testing.T) {
+	now := time.Date(2022, time.July, 3, 22, 5, 3, 0, time.UTC)
+	loggerOutput := new(bytes.Buffer)
+	l := NewLogger(
+		LogLevelInfo,
+		SetClockOpt(func() time.Time { return now }),
+		SetDefaultFlagsOpt(log.Lmsgprefix),
+		SetErrorLoggerOpt(Config{Outputs: []io.Writer{loggerOutput}, RateLimit: RateLimit{Rate: 2}}),
+	)
+
+	for i := 0; i < 5; i++ {
+		l.Errorf("error %d", i)
+	}
+	now = now.Add(time.Second)
+	l.Error("after")
+
+	want := "[ERROR]error 0\n" +
+		"[ERROR]error 1\n" +
+		"[ERROR]rate limit dropped 3 entries dropped=3\n" +
+		"[ERROR]after\n"
+	assert.Equal(t, want, loggerOutput.String())
+}
+
+func TestRateLimitOutput(t *testing.T) {
+	now := time.Date(2022, time.July, 3, 22, 5, 3, 0, time.UTC)
+	file, pagerOutput := new(bytes.Buffer), new(bytes.Buffer)
+	pager := RateLimitOutput(pagerOutput, RateLimit{Rate: 1})
+	l := NewLogger(
+		LogLevelInfo,
+		SetClockOpt(func() time.Time { return now }),
+		SetDefaultFlagsOpt(log.Lmsgprefix),
+		SetErrorLoggerOpt(Config{Outputs: []io.Writer{file, pager}}),
+		SetCriticalLoggerOpt(Config{Outputs: []io.Writer{file, pager}, Format: FormatLogfmt}),
+	)
+
+	l.Error("first")
+	l.Error("second")
+	l.Critical("third")
+	now = now.Add(2 * time.Second)
+	l.Critical("fourth")
+
+	assert.Equal(t, "[ERROR]first\n[ERROR]second\nlevel=critical msg=third\nlevel=critical msg=fourth\n", file.String())
+	assert.Equal(t, "[ERROR]first\n"+
+		"level=critical msg=\"rate limit dropped 2 entries\" dropped=2\n"+
+		"level=critical msg=fourth\n", pagerOutput.String())
+}
+
+func Test_logger_RateLimitTimer(t *testing.T) {
+	entries := make(chan Entry, 10)
+	l := NewLogger(
+		LogLevelInfo,
+		SetDefaultNonErrorOutputOpt(nil),
+		SetInfoLoggerOpt(Config{RateLimit: RateLimit{Rate: 100, Burst: 1}}),
+		SetEntryHookOpt(func(e Entry) { entries <- e }),
+	)
+
+	for i := 0; i < 3; i++ {
+		l.Info("test")
+	}
+	assert.Equal(t, "test", (<-entries).Message)
+
+	select {
+	case e := <-entries:
+		assert.Equal(t, "rate limit dropped 2 entries", e.Message)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the dropped entries weren't reported")
+	}
+}
+
+func TestRateLimitOutput_Disabled(t *testing.T) {
+	output := new(bytes.Buffer)
+	assert.Same(t, output, RateLimitOutput(output, RateLimit{}))
+}
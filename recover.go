@@ -0,0 +1,139 @@
+package jaglogger
+
+import (
+	"fmt"
+	"os"
+)
+
+// recoverAction is what Recover does after logging a panic.
+type recoverAction int
+
+const (
+	recoverRepanic recoverAction = iota
+	recoverExit
+	recoverSwallow
+)
+
+// recoverSettings holds the properties that can be modified by the RecoverOption type
+type recoverSettings struct {
+	action   recoverAction
+	exitCode int
+}
+
+// RecoverOption is a function type that allows modifications of what Recover does after logging a panic
+type RecoverOption func(*recoverSettings)
+
+// RecoverRepanicOpt makes Recover panic again with the recovered value after logging it. This is the default.
+func RecoverRepanicOpt() RecoverOption {
+	return func(s *recoverSettings) {
+		s.action = recoverRepanic
+	}
+}
+
+// RecoverExitOpt makes Recover exit the program with the given exit code after logging the panic.
+// Like with os.Exit, deferred functions aren't run.
+func RecoverExitOpt(code int) RecoverOption {
+	return func(s *recoverSettings) {
+		s.action = recoverExit
+		s.exitCode = code
+	}
+}
+
+// RecoverSwallowOpt makes Recover stop the panic after logging it, so the function that deferred
+// Recover returns normally.
+func RecoverSwallowOpt() RecoverOption {
+	return func(s *recoverSettings) {
+		s.action = recoverSwallow
+	}
+}
+
+// osExit is replaced by tests
+var osExit = os.Exit
+
+// Recover recovers from a panic, logs it to l at LogLevelCritical along with the stack trace of where
+// it happened, and calls Close, which flushes the outputs of the logger. It then panics again, unless another action
+// is chosen with RecoverExitOpt or RecoverSwallowOpt. Recover must be deferred directly:
+//
+//	defer jaglogger.Recover(logger)
+func Recover(l Logger, opts ...RecoverOption) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	if pl, ok := l.(logger); ok {
+		pl.logPanic(r)
+	} else {
+		l.Critical(fmt.Sprintf("panic: %v\n%s", r, panicStack()))
+	}
+	Close(l)
+	afterPanic(r, opts)
+}
+
+// logPanic logs the recovered value r with the stack trace of the panic.
+func (l logger) logPanic(r any) {
+	if lo, ok := l.outputs[LogLevelCritical]; ok && lo.enabled() {
+		e := l.panicEntry(lo, r)
+		lo.write(&e)
+	}
+}
+
+// panicEntry returns the entry that describes the recovered value r. It's called by the function
+// that recovered, so the stack trace of the panic is still there to be captured.
+func (l logger) panicEntry(lo *levelOutput, r any) Entry {
+	err, _ := r.(error)
+	e := Entry{
+		Level:   LogLevelCritical,
+		Time:    l.now(),
+		Message: fmt.Sprint("panic: ", r),
+		Fields:  l.entryFields(err),
+		Stack:   panicStack(),
+	}
+	if lo.caller&CallerDisabled == 0 && len(e.Stack) > 0 {
+		e.Caller = e.Stack[0]
+	}
+	return e
+}
+
+// afterPanic does what opts say should happen once the recovered value r has been logged.
+func afterPanic(r any, opts []RecoverOption) {
+	var settings recoverSettings
+	for _, opt := range opts {
+		opt(&settings)
+	}
+
+	switch settings.action {
+	case recoverExit:
+		osExit(settings.exitCode)
+	case recoverRepanic:
+		panic(r)
+	}
+}
+
+// panicStack returns the stack trace of the panicking goroutine, starting at the function that panicked.
+func panicStack() Stack {
+	// Skip panicStack and panicEntry, which leaves the function that recovered at the top
+	stack := captureStack(2, nil)
+	for i, c := range stack {
+		if c.Function == "runtime.gopanic" {
+			return stack[i+1:]
+		}
+	}
+	return stack
+}
+
+// flush flushes the outputs of every log level that have a Flush or Sync method, like a
+// *bufio.Writer or an *os.File.
+func (l logger) flush() {
+	for _, lo := range l.outputs {
+		lo.flush()
+	}
+}
+
+// Go runs f in a new goroutine that recovers from panics with Recover and the given options.
+func Go(l Logger, f func(), opts ...RecoverOption) {
+	go func() {
+		defer Recover(l, opts...)
+		f()
+	}()
+}
@@ -0,0 +1,104 @@
+package jaglogger
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func panicWith(v any) {
+	panic(v)
+}
+
+func Test_logger_Recover(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []RecoverOption
+		value     any
+		wantPanic bool
+		wantExit  int
+		wantMatch *regexp.Regexp
+	}{
+		{
+			name:      "Repanic",
+			value:     "boom",
+			wantPanic: true,
+			wantMatch: regexp.MustCompile(`^recover_test\.go:\d+: \[CRITICAL\]panic: boom\n\tgithub\.com/williabk198/jaglogger\.panicWith\n`),
+		},
+		{
+			name:      "Swallow",
+			opts:      []RecoverOption{RecoverSwallowOpt()},
+			value:     errors.New("boom"),
+			wantMatch: regexp.MustCompile(`^recover_test\.go:\d+: \[CRITICAL\]panic: boom\n\tgithub\.com/williabk198/jaglogger\.panicWith\n`),
+		},
+		{
+			name:      "Exit",
+			opts:      []RecoverOption{RecoverExitOpt(3)},
+			value:     "boom",
+			wantExit:  3,
+			wantMatch: regexp.MustCompile(`^recover_test\.go:\d+: \[CRITICAL\]panic: boom\n`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exitCode := -1
+			osExit = func(code int) { exitCode = code }
+			defer func() { osExit = os.Exit }()
+
+			loggerOutput := new(bytes.Buffer)
+			buffered := bufio.NewWriter(loggerOutput)
+			l := NewLogger(LogLevelInfo, SetCriticalLoggerOpt(Config{Outputs: []io.Writer{buffered}, Flags: log.Lmsgprefix | log.Lshortfile}))
+
+			run := func() {
+				defer Recover(l, tt.opts...)
+				panicWith(tt.value)
+			}
+			if tt.wantPanic {
+				assert.PanicsWithValue(t, tt.value, run)
+			} else {
+				assert.NotPanics(t, run)
+			}
+
+			if tt.wantExit != 0 {
+				assert.Equal(t, tt.wantExit, exitCode)
+			} else {
+				assert.Equal(t, -1, exitCode)
+			}
+			// The buffered output was flushed
+			assert.Regexp(t, tt.wantMatch, loggerOutput.String())
+		})
+	}
+}
+
+func Test_logger_RecoverNoPanic(t *testing.T) {
+	loggerOutput := new(bytes.Buffer)
+	l := NewLogger(LogLevelInfo, SetCriticalLoggerOpt(Config{Outputs: []io.Writer{loggerOutput}}))
+
+	func() {
+		defer Recover(l)
+	}()
+	assert.Empty(t, loggerOutput.String())
+}
+
+// chanWriter sends everything written to it on a channel.
+type chanWriter chan string
+
+func (w chanWriter) Write(p []byte) (int, error) {
+	w <- string(p)
+	return len(p), nil
+}
+
+func TestGo(t *testing.T) {
+	written := make(chanWriter, 1)
+	l := NewLogger(LogLevelInfo, SetCriticalLoggerOpt(Config{Outputs: []io.Writer{written}, Flags: log.Lmsgprefix}))
+
+	Go(l, func() { panicWith("boom") }, RecoverSwallowOpt())
+	assert.Regexp(t, `^\[CRITICAL\]panic: boom\n\tgithub\.com/williabk198/jaglogger\.panicWith\n`, <-written)
+}
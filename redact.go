@@ -0,0 +1,279 @@
+package jaglogger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Redactor is implemented by types that hold sensitive data, like credentials or personal
+// information. When a Redactor is logged, either as an argument of a logging call or as the value of
+// a field, the value returned by Redact is logged in its place. This doesn't need to be enabled with
+// an Option.
+type Redactor interface {
+	Redact() any
+}
+
+// RedactStrategy is how a sensitive value is replaced.
+type RedactStrategy int
+
+const (
+	// RedactMask replaces the value with "[REDACTED]".
+	RedactMask RedactStrategy = iota
+	// RedactHash replaces the value with the first 8 bytes of its SHA-256 hash, e.g.
+	// "sha256:9f86d081884c7d65", so entries about the same value can still be correlated. Keep in
+	// mind that values with few possibilities, like card numbers, can be recovered from their hash.
+	RedactHash
+	// RedactTruncate keeps the first 4 characters of the value, followed by "...". Values of 8
+	// characters or fewer are masked, so most of a value is never revealed.
+	RedactTruncate
+)
+
+// redactedText replaces the values masked with RedactMask.
+const redactedText = "[REDACTED]"
+
+func (rs RedactStrategy) redact(s string) string {
+	switch rs {
+	case RedactHash:
+		sum := sha256.Sum256([]byte(s))
+		return "sha256:" + hex.EncodeToString(sum[:8])
+	case RedactTruncate:
+		if utf8.RuneCountInString(s) <= 8 {
+			return redactedText
+		}
+		end := 0
+		for i := 0; i < 4; i++ {
+			_, size := utf8.DecodeRuneInString(s[end:])
+			end += size
+		}
+		return s[:end] + "..."
+	default:
+		return redactedText
+	}
+}
+
+// RedactPattern finds sensitive values in messages and in the string values of fields.
+type RedactPattern struct {
+	// Regexp matches the sensitive values. When it has a parenthesized subexpression, only the text
+	// matched by the first one is redacted, e.g. the token after "Bearer ".
+	Regexp *regexp.Regexp
+	// Verify, if set, is called with the text that would be redacted, which is only redacted if it
+	// returns true. It can rule out false positives that are hard to express in a regular expression.
+	Verify func(s string) bool
+}
+
+var (
+	// BearerTokenPattern matches the token of a bearer authorization, like in an HTTP header.
+	BearerTokenPattern = RedactPattern{Regexp: regexp.MustCompile(`(?i)\bbearer\s+([A-Za-z0-9\-._~+/]+=*)`)}
+	// AWSAccessKeyPattern matches AWS access key IDs.
+	AWSAccessKeyPattern = RedactPattern{Regexp: regexp.MustCompile(`\b(?:AKIA|ASIA|ABIA|ACCA)[A-Z0-9]{16}\b`)}
+	// AWSSecretKeyPattern matches AWS secret access keys that follow their usual name, like in a
+	// credentials file or environment variable.
+	AWSSecretKeyPattern = RedactPattern{Regexp: regexp.MustCompile(`(?i)aws_?secret_?access_?key\W{1,3}([A-Za-z0-9/+=]{40})\b`)}
+	// CreditCardPattern matches card numbers of 13 to 19 digits, which may be separated by spaces or
+	// dashes, and that have a valid Luhn check digit.
+	CreditCardPattern = RedactPattern{Regexp: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`), Verify: luhnValid}
+	// EmailPattern matches email addresses.
+	EmailPattern = RedactPattern{Regexp: regexp.MustCompile(`\b[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}\b`)}
+)
+
+// DefaultRedactPatterns holds all the patterns provided by this package.
+var DefaultRedactPatterns = []RedactPattern{BearerTokenPattern, AWSAccessKeyPattern, AWSSecretKeyPattern, CreditCardPattern, EmailPattern}
+
+// DefaultRedactKeys holds common keys of fields with sensitive values.
+var DefaultRedactKeys = []string{"password", "passwd", "secret", "token", "authorization", "cookie", "api_key", "apikey"}
+
+// luhnValid reports whether the digits in s have a valid Luhn check digit. Other characters are ignored.
+func luhnValid(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n > 0 && sum%10 == 0
+}
+
+// redaction holds the rules set with SetRedactKeysOpt and SetRedactPatternsOpt. A nil *redaction
+// only replaces Redactors.
+type redaction struct {
+	keys     map[string]RedactStrategy
+	patterns []strategyPattern
+}
+
+type strategyPattern struct {
+	RedactPattern
+	strategy RedactStrategy
+}
+
+// entry redacts the message and fields of e. The fields are copied before any of them is replaced,
+// since they may be shared with the logger that created e.
+func (r *redaction) entry(e *Entry) {
+	if r != nil {
+		e.Message = r.redactString(e.Message)
+	}
+	e.Fields = r.fields(e.Fields)
+}
+
+// fields returns fields with the sensitive values replaced. It returns fields itself when there are none.
+func (r *redaction) fields(fields []Field) []Field {
+	var redacted []Field
+	for i, f := range fields {
+		v, changed := r.value(f.Key, f.Value)
+		if changed && redacted == nil {
+			redacted = make([]Field, i, len(fields))
+			copy(redacted, fields[:i])
+		}
+		if redacted != nil {
+			redacted = append(redacted, Field{Key: f.Key, Value: v})
+		}
+	}
+	if redacted == nil {
+		return fields
+	}
+	return redacted
+}
+
+// value returns the value of the field with the given key, and whether it was replaced.
+func (r *redaction) value(key string, v any) (any, bool) {
+	changed := false
+	if rd, ok := v.(Redactor); ok {
+		v, changed = rd.Redact(), true
+	}
+	if _, ok := v.(Stack); ok {
+		return v, changed
+	}
+	if r != nil {
+		if strategy, ok := r.keyStrategy(key); ok {
+			return strategy.redact(valueString(v)), true
+		}
+	}
+	if obj, ok := objectValue(v); ok {
+		return redactedObject{r: r, obj: obj}, true
+	}
+	if r == nil || len(r.patterns) == 0 {
+		return v, changed
+	}
+
+	switch s := v.(type) {
+	case string:
+		if redacted := r.redactString(s); redacted != s {
+			return redacted, true
+		}
+	case []string:
+		var redacted []string
+		for i, str := range s {
+			red := r.redactString(str)
+			if red != str && redacted == nil {
+				redacted = append([]string(nil), s...)
+			}
+			if redacted != nil {
+				redacted[i] = red
+			}
+		}
+		if redacted != nil {
+			return redacted, true
+		}
+	case error, fmt.Stringer:
+		str := valueString(s)
+		if redacted := r.redactString(str); redacted != str {
+			return redacted, true
+		}
+	}
+	return v, changed
+}
+
+// keyStrategy returns the strategy for the values of key. Keys match case-insensitively, and the
+// part of a key after its last dot is matched as well, so "user.password" matches "password".
+func (r *redaction) keyStrategy(key string) (RedactStrategy, bool) {
+	if len(r.keys) == 0 {
+		return 0, false
+	}
+	key = strings.ToLower(key)
+	if strategy, ok := r.keys[key]; ok {
+		return strategy, true
+	}
+	if i := strings.LastIndexByte(key, '.'); i >= 0 {
+		strategy, ok := r.keys[key[i+1:]]
+		return strategy, ok
+	}
+	return 0, false
+}
+
+// redactString replaces the text matched by the patterns in s.
+func (r *redaction) redactString(s string) string {
+	for _, p := range r.patterns {
+		s = p.replace(s)
+	}
+	return s
+}
+
+func (p strategyPattern) replace(s string) string {
+	matches := p.Regexp.FindAllStringSubmatchIndex(s, -1)
+	if matches == nil {
+		return s
+	}
+
+	var b strings.Builder
+	last, replaced := 0, false
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if len(m) >= 4 && m[2] >= 0 {
+			start, end = m[2], m[3]
+		}
+		if p.Verify != nil && !p.Verify(s[start:end]) {
+			continue
+		}
+		b.WriteString(s[last:start])
+		b.WriteString(p.strategy.redact(s[start:end]))
+		last, replaced = end, true
+	}
+	if !replaced {
+		return s
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// redactedObject redacts the fields of obj when they're requested.
+type redactedObject struct {
+	r   *redaction
+	obj ObjectMarshaler
+}
+
+func (o redactedObject) LogFields() []Field {
+	return o.r.fields(o.obj.LogFields())
+}
+
+// redactArgs returns the arguments of a logging call with the Redactors replaced. It returns v
+// itself when there are none.
+func redactArgs(v []any) []any {
+	var redacted []any
+	for i, arg := range v {
+		rd, ok := arg.(Redactor)
+		if !ok {
+			continue
+		}
+		if redacted == nil {
+			redacted = append([]any(nil), v...)
+		}
+		redacted[i] = rd.Redact()
+	}
+	if redacted == nil {
+		return v
+	}
+	return redacted
+}
@@ -0,0 +1,147 @@
+package jaglogger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultSamplingInterval is used when Sampling.Interval isn't set.
+const defaultSamplingInterval = time.Second
+
+// Sampling limits how many entries with the same message are written per interval: the first First
+// of them are written, and after that every Thereafter-th one. Entries logged by the f functions,
+// like Infof, are grouped by their format string, and the others by their first line with the
+// numbers in it ignored. When entries are dropped, an entry reporting how many is written at the end of
+// the interval. Sampling is disabled when both First and Thereafter are 0.
+type Sampling struct {
+	// Interval is how long the counts last. Defaults to one second.
+	Interval time.Duration
+	// First is the number of entries per message template that are written at the start of each interval.
+	First int
+	// Thereafter is the rate at which entries are written once First have been. When it's 0, all of
+	// them are dropped.
+	Thereafter int
+}
+
+func (s Sampling) enabled() bool {
+	return s.First > 0 || s.Thereafter > 0
+}
+
+// sampler counts the entries of a single log level, and decides which ones are written.
+type sampler struct {
+	mu       sync.Mutex
+	config   Sampling
+	start    time.Time
+	counts   map[string]int
+	dropped  int
+	timer    *time.Timer
+	lastTime time.Time
+}
+
+func newSampler(config Sampling) *sampler {
+	if config.Interval <= 0 {
+		config.Interval = defaultSamplingInterval
+	}
+	return &sampler{config: config, counts: map[string]int{}}
+}
+
+// keep reports whether e should be written to lo, counting it under key, or under the sampleKey of
+// its message if key is "". When an interval in which entries were dropped has ended, the entry
+// reporting them is written first.
+func (s *sampler) keep(lo *levelOutput, e *Entry, key string) bool {
+	if key == "" {
+		key = sampleKey(e.Message)
+	}
+
+	s.mu.Lock()
+	var summary *Entry
+	if s.start.IsZero() || e.Time.Sub(s.start) >= s.config.Interval || e.Time.Before(s.start) {
+		summary = s.summary(e.Level)
+		s.start = e.Time
+		for k := range s.counts {
+			delete(s.counts, k)
+		}
+	}
+
+	n := s.counts[key] + 1
+	s.counts[key] = n
+	keep := n <= s.config.First || (s.config.Thereafter > 0 && (n-s.config.First)%s.config.Thereafter == 0)
+	if !keep {
+		s.dropped++
+		s.lastTime = e.Time
+		if s.timer == nil {
+			level := e.Level
+			s.timer = time.AfterFunc(s.start.Add(s.config.Interval).Sub(e.Time), func() { s.flush(lo, level) })
+		}
+	}
+	s.mu.Unlock()
+
+	if summary != nil {
+		lo.write(summary)
+	}
+	return keep
+}
+
+// flush writes the entry reporting the dropped entries, if there are any. It's called by a timer
+// at the end of the interval in which they were dropped, in case no entry comes after them.
+func (s *sampler) flush(lo *levelOutput, level LogLevel) {
+	s.mu.Lock()
+	summary := s.summary(level)
+	s.mu.Unlock()
+
+	if summary != nil {
+		lo.write(summary)
+	}
+}
+
+// summary returns the entry reporting the entries dropped since the last one, or nil if there are
+// none. s.mu must be held.
+func (s *sampler) summary(level LogLevel) *Entry {
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	if s.dropped == 0 {
+		return nil
+	}
+
+	e := &Entry{
+		Level:   level,
+		Time:    s.lastTime,
+		Message: fmt.Sprintf("sampling dropped %d entries", s.dropped),
+		Fields:  []Field{Int("dropped", s.dropped)},
+	}
+	s.dropped = 0
+	return e
+}
+
+// sampleKey returns the first line of msg with every run of digits replaced by a single 0. It's a
+// cheaper MessageTemplate, since it runs for every sampled entry.
+func sampleKey(msg string) string {
+	if i := strings.IndexByte(msg, '\n'); i >= 0 {
+		msg = msg[:i]
+	}
+	i := strings.IndexAny(msg, "0123456789")
+	if i < 0 {
+		return msg
+	}
+
+	key := make([]byte, i, len(msg))
+	copy(key, msg)
+	digits := false
+	for ; i < len(msg); i++ {
+		c := msg[i]
+		if c >= '0' && c <= '9' {
+			if !digits {
+				key = append(key, '0')
+			}
+			digits = true
+			continue
+		}
+		digits = false
+		key = append(key, c)
+	}
+	return string(key)
+}
@@ -0,0 +1,177 @@
+package jaglogger
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_logger_Sampling(t *testing.T) {
+	now := time.Date(2022, time.July, 3, 22, 5, 3, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	loggerOutput := new(bytes.Buffer)
+	l := NewLogger(
+		LogLevelDebug,
+		SetClockOpt(clock),
+		SetDefaultFlagsOpt(log.Lmsgprefix),
+		SetDefaultNonErrorOutputOpt([]io.Writer{loggerOutput}),
+		SetInfoLoggerOpt(Config{Sampling: &Sampling{Interval: time.Minute, First: 2, Thereafter: 3}}),
+	)
+
+	for i := 1; i <= 10; i++ {
+		l.Infof("request %d", i)
+		l.Debugf("debug %d", i)
+	}
+	l.Info("other")
+	now = now.Add(time.Minute)
+	l.Info("request 11")
+
+	want := "[INFO]request 1\n" +
+		"[INFO]request 2\n" +
+		"[INFO]request 5\n" +
+		"[INFO]request 8\n" +
+		"[INFO]other\n" +
+		"[INFO]sampling dropped 6 entries dropped=6\n" +
+		"[INFO]request 11\n"
+	got := ""
+	for _, line := range bytes.SplitAfter(loggerOutput.Bytes(), []byte("\n")) {
+		if !bytes.HasPrefix(line, []byte("[DEBUG]")) {
+			got += string(line)
+		}
+	}
+	assert.Equal(t, want, got)
+	if DebugCompiled {
+		assert.Equal(t, 10, bytes.Count(loggerOutput.Bytes(), []byte("[DEBUG]")))
+	}
+}
+
+func Test_logger_SamplingSummaryTimer(t *testing.T) {
+	entries := make(chan Entry, 10)
+	l := NewLogger(
+		LogLevelInfo,
+		SetDefaultNonErrorOutputOpt(nil),
+		SetDefaultSamplingOpt(Sampling{Interval: 10 * time.Millisecond, First: 1}),
+		SetEntryHookOpt(func(e Entry) { entries <- e }),
+	)
+
+	for i := 0; i < 3; i++ {
+		l.Info("test")
+	}
+	assert.Equal(t, "test", (<-entries).Message)
+
+	select {
+	case e := <-entries:
+		assert.Equal(t, "sampling dropped 2 entries", e.Message)
+		assert.Equal(t, []Field{Int("dropped", 2)}, e.Fields)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the summary wasn't written")
+	}
+}
+
+func Test_logger_SamplingSummaryTimerEndOfInterval(t *testing.T) {
+	// The summary is written at the end of the interval, not an interval after the first dropped entry
+	start := time.Date(2022, time.July, 3, 22, 5, 3, 0, time.UTC)
+	now := start
+	entries := make(chan Entry, 10)
+	l := NewLogger(
+		LogLevelInfo,
+		SetClockOpt(func() time.Time { return now }),
+		SetDefaultNonErrorOutputOpt(nil),
+		SetDefaultSamplingOpt(Sampling{Interval: time.Hour, First: 1}),
+		SetEntryHookOpt(func(e Entry) { entries <- e }),
+	)
+
+	l.Info("test")
+	assert.Equal(t, "test", (<-entries).Message)
+	now = start.Add(time.Hour - 10*time.Millisecond)
+	l.Info("test")
+
+	select {
+	case e := <-entries:
+		assert.Equal(t, "sampling dropped 1 entries", e.Message)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the summary wasn't written at the end of the interval")
+	}
+}
+
+func Test_logger_SamplingDisabledPerLevel(t *testing.T) {
+	loggerOutput := new(bytes.Buffer)
+	l := NewLogger(
+		LogLevelInfo,
+		SetDefaultFlagsOpt(log.Lmsgprefix),
+		SetDefaultErrorOutputsOpt([]io.Writer{loggerOutput}),
+		SetDefaultSamplingOpt(Sampling{Interval: time.Hour, First: 1}),
+		SetErrorLoggerOpt(Config{Sampling: &Sampling{}}),
+	)
+
+	for i := 0; i < 2; i++ {
+		l.Warning("warning")
+		l.Error("error")
+	}
+	assert.Equal(t, "[WARNING]warning\n[ERROR]error\n[ERROR]error\n", loggerOutput.String())
+}
+
+func Test_sampleKey(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  string
+		want string
+	}{
+		{name: "No Numbers", msg: "request received", want: "request received"},
+		{name: "Numbers", msg: "request 42 took 1.25s", want: "request 0 took 0.0s"},
+		{name: "First Line", msg: "panic 7\ngoroutine 1", want: "panic 0"},
+		{name: "Only Number", msg: "12345", want: "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sampleKey(tt.msg))
+		})
+	}
+}
+
+func Test_logger_SamplingFormatKey(t *testing.T) {
+	loggerOutput := new(bytes.Buffer)
+	l := NewLogger(
+		LogLevelInfo,
+		SetInfoLoggerOpt(Config{
+			Outputs:  []io.Writer{loggerOutput},
+			Flags:    log.Lmsgprefix,
+			Sampling: &Sampling{Interval: time.Hour, First: 1},
+		}),
+	)
+
+	// Entries made from the same format string are sampled together, whatever their arguments
+	l.Infof("user %s logged in", "alice")
+	l.Infof("user %s logged in", "bob")
+	l.Infof("user %s logged out", "alice")
+	assert.Equal(t, "[INFO]user alice logged in\n[INFO]user alice logged out\n", loggerOutput.String())
+}
+
+func Benchmark_logger_Sampling(b *testing.B) {
+	l := NewLogger(
+		LogLevelInfo,
+		SetInfoLoggerOpt(Config{
+			Outputs:  []io.Writer{io.Discard},
+			Caller:   CallerDisabled,
+			Sampling: &Sampling{Interval: time.Hour, First: 1},
+		}),
+	)
+
+	b.Run("Dropped", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.Info("request 42 from 10.0.0.1 took 1.25s")
+		}
+	})
+	b.Run("Dropped Format", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l.Infof("request %d from %s took %s", i, "10.0.0.1", time.Second)
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package jaglogger
+
+import (
+	"unicode/utf8"
+)
+
+// MultilinePolicy is how the sanitizer enabled with SetSanitizeOpt writes the newlines in messages.
+type MultilinePolicy int
+
+const (
+	// MultilineEscape writes newlines as `\n`, so every entry is a single line.
+	MultilineEscape MultilinePolicy = iota
+	// MultilineIndent keeps newlines, but indents the lines after the first one with a tab. Those
+	// lines can't be mistaken for the start of another entry, by a reader or a TextScanner.
+	MultilineIndent
+)
+
+// appendSanitized appends s to buf with the unsafe characters escaped, as described by SetSanitizeOpt.
+func appendSanitized(buf []byte, s string, multiline MultilinePolicy) []byte {
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == '\n' && multiline == MultilineIndent:
+			buf = append(buf, '\n', '\t')
+		case r == '\n':
+			buf = append(buf, '\\', 'n')
+		case r == '\r':
+			buf = append(buf, '\\', 'r')
+		case r == '\t':
+			buf = append(buf, '\t')
+		case r == utf8.RuneError && size == 1, r < ' ', r == 0x7f:
+			// Invalid UTF-8 is escaped byte by byte, and ESC covers ANSI escape codes
+			buf = append(buf, '\\', 'x', hexDigits[s[i]>>4], hexDigits[s[i]&0xf])
+		case unsafeRune(r):
+			buf = append(buf, '\\', 'u', hexDigits[r>>12&0xf], hexDigits[r>>8&0xf], hexDigits[r>>4&0xf], hexDigits[r&0xf])
+		default:
+			buf = append(buf, s[i:i+size]...)
+		}
+		i += size
+	}
+	return buf
+}
+
+// unsafeRune reports whether r is a C1 control character, which some terminals interpret like an
+// escape code, a Unicode line or paragraph separator, or a bidirectional override or isolate,
+// which can make text display in a different order than it's read.
+func unsafeRune(r rune) bool {
+	return (r >= 0x80 && r <= 0x9f) ||
+		r == '\u2028' || r == '\u2029' ||
+		(r >= '\u202a' && r <= '\u202e') ||
+		(r >= '\u2066' && r <= '\u2069')
+}
@@ -0,0 +1,101 @@
+package jaglogger
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_appendSanitized(t *testing.T) {
+	tests := []struct {
+		name      string
+		s         string
+		multiline MultilinePolicy
+		want      string
+	}{
+		{name: "Plain", s: "héllo\tworld", want: "héllo\tworld"},
+		{name: "Forged Entry", s: "user\n[CRITICAL]forged", want: `user\n[CRITICAL]forged`},
+		{name: "Forged Entry Indented", s: "user\n[CRITICAL]forged", multiline: MultilineIndent, want: "user\n\t[CRITICAL]forged"},
+		{name: "Carriage Return", s: "ok\roverwritten", multiline: MultilineIndent, want: `ok\roverwritten`},
+		{name: "ANSI Escape", s: "\x1b[31mred\x1b[0m", want: `\x1b[31mred\x1b[0m`},
+		{name: "Control Characters", s: "a\x00b\x07c\x7f", want: `a\x00b\x07c\x7f`},
+		{name: "C1 Control", s: "a\u009b31mb", want: `a\u009b31mb`},
+		{name: "Bidi Override", s: "file\u202egnp.exe", want: `file\u202egnp.exe`},
+		{name: "Line Separator", s: "a\u2028b", want: `a\u2028b`},
+		{name: "Invalid UTF-8", s: "a\xffb\xc3", want: `a\xffb\xc3`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(appendSanitized(nil, tt.s, tt.multiline))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_logger_Sanitize(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []Option
+		format Format
+		want   string
+	}{
+		{
+			name: "Disabled",
+			want: "[INFO]login failed for admin\n[CRITICAL]forged a\nb=\"\\x1b[2J\"\n",
+		},
+		{
+			name: "Escape",
+			opts: []Option{SetSanitizeOpt(MultilineEscape)},
+			want: `[INFO]login failed for admin\n[CRITICAL]forged a\nb="\x1b[2J"` + "\n",
+		},
+		{
+			name: "Indent",
+			opts: []Option{SetSanitizeOpt(MultilineIndent)},
+			want: "[INFO]login failed for admin\n\t[CRITICAL]forged a\\nb=\"\\x1b[2J\"\n",
+		},
+		{
+			name:   "Console",
+			opts:   []Option{SetSanitizeOpt(MultilineEscape)},
+			format: FormatConsole,
+			want:   `[INFO]     login failed for admin\n[CRITICAL]forged  a\nb="\x1b[2J"` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loggerOutput := new(bytes.Buffer)
+			opts := append([]Option{SetInfoLoggerOpt(Config{Outputs: []io.Writer{loggerOutput}, Flags: log.Lmsgprefix, Format: tt.format})}, tt.opts...)
+			l := NewLogger(LogLevelInfo, opts...)
+
+			With(l, "a\nb", "\x1b[2J").Infof("login failed for %s", "admin\n[CRITICAL]forged")
+			assert.Equal(t, tt.want, loggerOutput.String())
+		})
+	}
+}
+
+func Test_logger_SanitizeRoundTrip(t *testing.T) {
+	loggerOutput := new(bytes.Buffer)
+	l := NewLogger(
+		LogLevelInfo,
+		SetDefaultFlagsOpt(log.Lmsgprefix),
+		SetDefaultErrorOutputsOpt([]io.Writer{loggerOutput}),
+		SetDefaultNonErrorOutputOpt([]io.Writer{loggerOutput}),
+		SetSanitizeOpt(MultilineIndent),
+	)
+	l.Info("first\n[CRITICAL]forged")
+	l.Error("second")
+
+	s := NewTextScanner(loggerOutput, TextParser{Flags: log.Lmsgprefix})
+	var got []Entry
+	for s.Scan() {
+		e, err := s.Entry()
+		assert.NoError(t, err)
+		got = append(got, e)
+	}
+	assert.Equal(t, []Entry{
+		{Level: LogLevelInfo, Message: "first\n\t[CRITICAL]forged"},
+		{Level: LogLevelError, Message: "second"},
+	}, got)
+}
@@ -0,0 +1,125 @@
+package jaglogger
+
+import (
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Stack is a stack trace, starting with the innermost function call.
+type Stack []Caller
+
+// stackFromPCs returns the stack of the program counters returned by runtime.Callers.
+func stackFromPCs(pcs []uintptr) Stack {
+	if len(pcs) == 0 {
+		return nil
+	}
+	stack := make(Stack, 0, len(pcs))
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		// Like in the stack traces of panics, the function every goroutine starts from is left out
+		if frame.Function != "runtime.goexit" {
+			stack = append(stack, callerFromFrame(frame))
+		}
+		if !more {
+			return stack
+		}
+	}
+}
+
+// maxStackDepth is the number of function calls captured by captureStack.
+const maxStackDepth = 64
+
+// captureStack returns the stack trace of the current goroutine, starting at the given depth.
+// Like with lookupCaller, a skip of 0 is the caller of captureStack. Functions marked as helpers
+// at the top of the stack are left out, as are the functions of this package.
+func captureStack(skip int, helpers *helperFuncs) Stack {
+	var pcs [maxStackDepth]uintptr
+	// Skip runtime.Callers and captureStack
+	n := runtime.Callers(skip+2, pcs[:])
+	stack := stackFromPCs(pcs[:n])
+
+	trimmed := stack[:0]
+	top := true
+	for _, c := range stack {
+		if top && helpers.any() && helpers.contains(c.Function) {
+			continue
+		}
+		top = false
+		if isPackageFrame(c) {
+			continue
+		}
+		trimmed = append(trimmed, c)
+	}
+	return trimmed
+}
+
+// packagePath is the import path of this package.
+var packagePath = reflect.TypeOf(logger{}).PkgPath()
+
+// isPackageFrame reports whether c is in this package, not counting its tests.
+func isPackageFrame(c Caller) bool {
+	return strings.HasPrefix(c.Function, packagePath+".") && !strings.HasSuffix(c.File, "_test.go")
+}
+
+// String returns the stack in the format used by panics:
+//
+//	main.handle
+//		/path/to/main.go:23
+//	main.main
+//		/path/to/main.go:8
+func (s Stack) String() string {
+	return string(s.appendText(nil, ""))
+}
+
+// appendText appends the stack to buf in the format returned by String, prefixing each line with indent.
+func (s Stack) appendText(buf []byte, indent string) []byte {
+	for i, c := range s {
+		if i > 0 {
+			buf = append(buf, '\n')
+		}
+		buf = append(buf, indent...)
+		buf = append(buf, c.Function...)
+		buf = append(buf, '\n')
+		buf = append(buf, indent...)
+		buf = append(buf, '\t')
+		buf = append(buf, c.File...)
+		buf = append(buf, ':')
+		buf = strconv.AppendInt(buf, int64(c.Line), 10)
+	}
+	return buf
+}
+
+// appendBlock appends the stack to buf as an indented block of lines that follows an entry of the
+// text formats. When key isn't empty, the block starts with a line with the key.
+func (s Stack) appendBlock(buf []byte, key string) []byte {
+	indent := "\t"
+	if key != "" {
+		buf = append(buf, '\t')
+		buf = append(buf, key...)
+		buf = append(buf, ":\n"...)
+		indent = "\t\t"
+	}
+	buf = s.appendText(buf, indent)
+	return append(buf, '\n')
+}
+
+// appendJSON appends the stack to buf as an array of objects with the func, file and line of each call.
+func (s Stack) appendJSON(buf []byte) []byte {
+	buf = append(buf, '[')
+	for i, c := range s {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, `{"`+functionKey+`":`...)
+		buf = appendQuoted(buf, c.Function)
+		buf = append(buf, `,"file":`...)
+		buf = appendQuoted(buf, c.File)
+		buf = append(buf, `,"line":`...)
+		buf = strconv.AppendInt(buf, int64(c.Line), 10)
+		buf = append(buf, '}')
+	}
+	return append(buf, ']')
+}
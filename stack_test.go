@@ -0,0 +1,91 @@
+package jaglogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func logStackThroughHelper(l Logger) {
+	Helper(l)
+	l.Critical("test")
+}
+
+func Test_logger_StackTrace(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    Format
+		wantMatch *regexp.Regexp
+	}{
+		{
+			name:   "Text",
+			format: FormatText,
+			wantMatch: regexp.MustCompile(`^\[CRITICAL\]test\n` +
+				`\tgithub\.com/williabk198/jaglogger\.Test_logger_StackTrace\.func\d+\n\t\t.*/stack_test\.go:\d+\n` +
+				`\ttesting\.tRunner\n\t\t.*/testing\.go:\d+\n$`),
+		},
+		{
+			name:   "Logfmt",
+			format: FormatLogfmt,
+			wantMatch: regexp.MustCompile(`^level=critical msg=test stack="github\.com/williabk198/jaglogger\.Test_logger_StackTrace\.func\d+` +
+				`\\n\\t.*/stack_test\.go:\d+\\ntesting\.tRunner\\n\\t.*/testing\.go:\d+"\n$`),
+		},
+		{
+			name:   "JSON",
+			format: FormatJSON,
+			wantMatch: regexp.MustCompile(`^\{"level":"critical","msg":"test","stack":\[` +
+				`\{"func":"github\.com/williabk198/jaglogger\.Test_logger_StackTrace\.func\d+","file":".*/stack_test\.go","line":\d+\},` +
+				`\{"func":"testing\.tRunner","file":".*/testing\.go","line":\d+\}\]\}\n$`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loggerOutput := new(bytes.Buffer)
+			l := NewLogger(LogLevelInfo, SetCriticalLoggerOpt(Config{
+				Outputs:    []io.Writer{loggerOutput},
+				Flags:      log.Lmsgprefix,
+				Format:     tt.format,
+				StackTrace: true,
+			}))
+
+			logStackThroughHelper(l)
+			assert.Regexp(t, tt.wantMatch, loggerOutput.String())
+		})
+	}
+}
+
+func Test_logger_StackTraceLevels(t *testing.T) {
+	loggerOutput := new(bytes.Buffer)
+	l := NewLogger(
+		LogLevelInfo,
+		SetDefaultFlagsOpt(log.Lmsgprefix),
+		SetDefaultErrorOutputsOpt([]io.Writer{loggerOutput}),
+		SetCriticalLoggerOpt(Config{Outputs: []io.Writer{loggerOutput}, StackTrace: true}),
+	)
+
+	l.Error("no stack")
+	assert.Equal(t, "[ERROR]no stack\n", loggerOutput.String())
+
+	loggerOutput.Reset()
+	w := Writer(l, LogLevelCritical)
+	w.Write([]byte("stack\n"))
+	lines := strings.Split(loggerOutput.String(), "\n")
+	if assert.Greater(t, len(lines), 2) {
+		assert.Equal(t, "[CRITICAL]stack", lines[0])
+		// The stack starts at the caller of Write, without any of the frames of the Writer itself
+		assert.Regexp(t, `^\tgithub\.com/williabk198/jaglogger\.Test_logger_StackTraceLevels$`, lines[1])
+	}
+}
+
+func TestStack_appendJSON(t *testing.T) {
+	s := Stack{{Function: "main.main", File: "/app/main.go", Line: 8, Defined: true}}
+	got := s.appendJSON(nil)
+	assert.True(t, json.Valid(got))
+	assert.Equal(t, `[{"func":"main.main","file":"/app/main.go","line":8}]`, string(got))
+}
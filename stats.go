@@ -0,0 +1,267 @@
+package jaglogger
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Stats collects statistics about log entries, such as the ones read by a Scanner, to find out which
+// messages spiked and where they came from. Entries are added with Add, and summarized with Report.
+type Stats struct {
+	bucketSize time.Duration
+	total      int
+	unparsed   int
+	first      time.Time
+	last       time.Time
+	levels     map[LogLevel]int
+	// buckets are keyed by the Unix time of their start, since equal times can have different locations
+	buckets  map[int64]*statsBucket
+	callers  map[string]*StatsCount
+	messages map[string]*StatsCount
+}
+
+// NewStats returns an empty Stats that counts the entries per log level in buckets of the given size.
+// The buckets are aligned to the zero time, so a bucket size of an hour gives buckets that start on
+// the hour.
+func NewStats(bucketSize time.Duration) *Stats {
+	return &Stats{
+		bucketSize: bucketSize,
+		levels:     make(map[LogLevel]int),
+		buckets:    make(map[int64]*statsBucket),
+		callers:    make(map[string]*StatsCount),
+		messages:   make(map[string]*StatsCount),
+	}
+}
+
+// Add counts e. Entries without a time are counted everywhere except in the buckets.
+func (s *Stats) Add(e Entry) {
+	s.total++
+	s.levels[e.Level]++
+
+	if !e.Time.IsZero() {
+		if s.first.IsZero() || e.Time.Before(s.first) {
+			s.first = e.Time
+		}
+		if e.Time.After(s.last) {
+			s.last = e.Time
+		}
+
+		start := e.Time.Truncate(s.bucketSize)
+		bucket, ok := s.buckets[start.UnixNano()]
+		if !ok {
+			bucket = &statsBucket{start: start, levels: make(map[LogLevel]int)}
+			s.buckets[start.UnixNano()] = bucket
+		}
+		bucket.levels[e.Level]++
+	}
+
+	if e.Caller.Defined {
+		countItem(s.callers, callerName(e.Caller), e.Time)
+	}
+	countItem(s.messages, MessageTemplate(e.Message), e.Time)
+}
+
+// statsBucket holds the number of entries per log level in the bucket that starts at start.
+type statsBucket struct {
+	start  time.Time
+	levels map[LogLevel]int
+}
+
+// AddUnparsed counts an entry that couldn't be parsed.
+func (s *Stats) AddUnparsed() {
+	s.unparsed++
+}
+
+func countItem(items map[string]*StatsCount, name string, t time.Time) {
+	item, ok := items[name]
+	if !ok {
+		item = &StatsCount{Name: name}
+		items[name] = item
+	}
+	item.Count++
+	if !t.IsZero() {
+		if item.First.IsZero() || t.Before(item.First) {
+			item.First = t
+		}
+		if t.After(item.Last) {
+			item.Last = t
+		}
+	}
+}
+
+// callerName returns the file and line of c, or its function when the file isn't known.
+func callerName(c Caller) string {
+	if c.File == "" {
+		return c.Function
+	}
+	return c.File + ":" + strconv.Itoa(c.Line)
+}
+
+var (
+	uuidPattern   = regexp.MustCompile(`\b[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\b`)
+	hexPattern    = regexp.MustCompile(`\b(0x[0-9a-fA-F]+|[0-9a-fA-F]{8,})\b`)
+	numberPattern = regexp.MustCompile(`\d+(\.\d+)?`)
+)
+
+// MessageTemplate returns the first line of msg, with the parts that usually differ between messages
+// logged by the same call replaced by placeholders: UUIDs by <uuid>, hexadecimal IDs by <hex> and
+// numbers by <n>.
+func MessageTemplate(msg string) string {
+	if i := strings.IndexByte(msg, '\n'); i >= 0 {
+		msg = msg[:i]
+	}
+	msg = uuidPattern.ReplaceAllString(msg, "<uuid>")
+	msg = hexPattern.ReplaceAllStringFunc(msg, func(id string) string {
+		// Long decimal numbers are left to numberPattern
+		if strings.Trim(id, "0123456789") == "" {
+			return id
+		}
+		return "<hex>"
+	})
+	return numberPattern.ReplaceAllString(msg, "<n>")
+}
+
+// StatsReport is a summary of the entries counted by Stats. The log levels are keyed by their
+// lowercase names, as written by the structured formats.
+type StatsReport struct {
+	Total       int            `json:"total"`
+	Unparsed    int            `json:"unparsed"`
+	First       time.Time      `json:"first"`
+	Last        time.Time      `json:"last"`
+	Levels      map[string]int `json:"levels"`
+	BucketSize  string         `json:"bucket_size"`
+	Buckets     []StatsBucket  `json:"buckets"`
+	TopCallers  []StatsCount   `json:"top_callers"`
+	TopMessages []StatsCount   `json:"top_messages"`
+}
+
+// StatsBucket holds the number of entries per log level in the bucket that starts at Start.
+type StatsBucket struct {
+	Start  time.Time      `json:"start"`
+	Levels map[string]int `json:"levels"`
+}
+
+// StatsCount holds how many entries have something in common, such as their caller or message template,
+// along with the times of the first and last of them.
+type StatsCount struct {
+	Name  string    `json:"name"`
+	Count int       `json:"count"`
+	First time.Time `json:"first"`
+	Last  time.Time `json:"last"`
+}
+
+// Report summarizes the entries counted so far. The top callers and message templates are limited to
+// the given number, with ties broken by which occurred first.
+func (s *Stats) Report(top int) *StatsReport {
+	r := &StatsReport{
+		Total:       s.total,
+		Unparsed:    s.unparsed,
+		First:       s.first,
+		Last:        s.last,
+		Levels:      levelNames(s.levels),
+		BucketSize:  s.bucketSize.String(),
+		Buckets:     make([]StatsBucket, 0, len(s.buckets)),
+		TopCallers:  topCounts(s.callers, top),
+		TopMessages: topCounts(s.messages, top),
+	}
+	for _, b := range s.buckets {
+		r.Buckets = append(r.Buckets, StatsBucket{Start: b.start, Levels: levelNames(b.levels)})
+	}
+	sort.Slice(r.Buckets, func(i, j int) bool {
+		return r.Buckets[i].Start.Before(r.Buckets[j].Start)
+	})
+	return r
+}
+
+func levelNames(counts map[LogLevel]int) map[string]int {
+	names := make(map[string]int, len(counts))
+	for level, count := range counts {
+		names[level.name()] = count
+	}
+	return names
+}
+
+func topCounts(items map[string]*StatsCount, top int) []StatsCount {
+	counts := make([]StatsCount, 0, len(items))
+	for _, item := range items {
+		counts = append(counts, *item)
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Count != counts[j].Count {
+			return counts[i].Count > counts[j].Count
+		}
+		if !counts[i].First.Equal(counts[j].First) {
+			return counts[i].First.Before(counts[j].First)
+		}
+		return counts[i].Name < counts[j].Name
+	})
+	if top >= 0 && len(counts) > top {
+		counts = counts[:top]
+	}
+	return counts
+}
+
+// reportLevels are the log levels in the order they're listed by WriteText.
+var reportLevels = []LogLevel{LogLevelCritical, LogLevelError, LogLevelWarning, LogLevelNotice, LogLevelInfo, LogLevelDebug}
+
+// WriteText writes r to w as a human readable report.
+func (r *StatsReport) WriteText(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Entries:\t%d\n", r.Total)
+	if r.Unparsed > 0 {
+		fmt.Fprintf(tw, "Unparsed:\t%d\n", r.Unparsed)
+	}
+	fmt.Fprintf(tw, "First:\t%s\n", reportTime(r.First))
+	fmt.Fprintf(tw, "Last:\t%s\n", reportTime(r.Last))
+
+	fmt.Fprintln(tw, "\nLEVEL\tCOUNT")
+	for _, level := range reportLevels {
+		fmt.Fprintf(tw, "%s\t%d\n", level.name(), r.Levels[level.name()])
+	}
+	if n := r.Levels[LogLevel(0).name()]; n > 0 {
+		fmt.Fprintf(tw, "unknown\t%d\n", n)
+	}
+
+	if len(r.Buckets) > 0 {
+		fmt.Fprintf(tw, "\nPER %s", r.BucketSize)
+		for _, level := range reportLevels {
+			fmt.Fprintf(tw, "\t%s", strings.ToUpper(level.name()))
+		}
+		fmt.Fprintln(tw)
+		for _, b := range r.Buckets {
+			fmt.Fprint(tw, reportTime(b.Start))
+			for _, level := range reportLevels {
+				fmt.Fprintf(tw, "\t%d", b.Levels[level.name()])
+			}
+			fmt.Fprintln(tw)
+		}
+	}
+
+	writeCounts := func(title string, counts []StatsCount) {
+		if len(counts) == 0 {
+			return
+		}
+		fmt.Fprintf(tw, "\nCOUNT\tFIRST\tLAST\t%s\n", title)
+		for _, c := range counts {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", c.Count, reportTime(c.First), reportTime(c.Last), c.Name)
+		}
+	}
+	writeCounts("CALLER", r.TopCallers)
+	writeCounts("MESSAGE", r.TopMessages)
+
+	return tw.Flush()
+}
+
+func reportTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
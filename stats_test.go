@@ -0,0 +1,123 @@
+package jaglogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageTemplate(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  string
+		want string
+	}{
+		{
+			name: "Numbers",
+			msg:  "request 1234 took 1.5s",
+			want: "request <n> took <n>s",
+		},
+		{
+			name: "IDs",
+			msg:  "user 3f2504e0-4f89-11d3-9a0c-0305e82c3301 session deadbeef01 at 0x1f",
+			want: "user <uuid> session <hex> at <hex>",
+		},
+		{
+			name: "Long Number",
+			msg:  "order 12345678 shipped",
+			want: "order <n> shipped",
+		},
+		{
+			name: "First Line Only",
+			msg:  "panic: oops\ngoroutine 1 [running]:",
+			want: "panic: oops",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MessageTemplate(tt.msg))
+		})
+	}
+}
+
+func TestStats(t *testing.T) {
+	at := func(min, sec int) time.Time {
+		return time.Date(2022, time.July, 3, 22, min, sec, 0, time.UTC)
+	}
+	dbCaller := Caller{File: "db.go", Line: 23, Defined: true}
+	mainCaller := Caller{Function: "main.main", Defined: true}
+
+	s := NewStats(time.Minute)
+	s.Add(Entry{Level: LogLevelInfo, Time: at(5, 1), Caller: mainCaller, Message: "started"})
+	s.Add(Entry{Level: LogLevelError, Time: at(5, 2), Caller: dbCaller, Message: "query 1 failed"})
+	s.Add(Entry{Level: LogLevelError, Time: at(6, 3), Caller: dbCaller, Message: "query 2 failed"})
+	s.Add(Entry{Level: LogLevelWarning, Caller: dbCaller, Message: "query 3 failed"})
+	s.AddUnparsed()
+
+	r := s.Report(1)
+	assert.Equal(t, &StatsReport{
+		Total:      4,
+		Unparsed:   1,
+		First:      at(5, 1),
+		Last:       at(6, 3),
+		Levels:     map[string]int{"info": 1, "error": 2, "warning": 1},
+		BucketSize: "1m0s",
+		Buckets: []StatsBucket{
+			{Start: at(5, 0), Levels: map[string]int{"info": 1, "error": 1}},
+			{Start: at(6, 0), Levels: map[string]int{"error": 1}},
+		},
+		TopCallers:  []StatsCount{{Name: "db.go:23", Count: 3, First: at(5, 2), Last: at(6, 3)}},
+		TopMessages: []StatsCount{{Name: "query <n> failed", Count: 3, First: at(5, 2), Last: at(6, 3)}},
+	}, r)
+
+	text := new(bytes.Buffer)
+	assert.NoError(t, r.WriteText(text))
+	assert.Equal(t, `Entries:   4
+Unparsed:  1
+First:     2022-07-03T22:05:01Z
+Last:      2022-07-03T22:06:03Z
+
+LEVEL     COUNT
+critical  0
+error     2
+warning   1
+notice    0
+info      1
+debug     0
+
+PER 1m0s              CRITICAL  ERROR  WARNING  NOTICE  INFO  DEBUG
+2022-07-03T22:05:00Z  0         1      0        0       1     0
+2022-07-03T22:06:00Z  0         1      0        0       0     0
+
+COUNT  FIRST                 LAST                  CALLER
+3      2022-07-03T22:05:02Z  2022-07-03T22:06:03Z  db.go:23
+
+COUNT  FIRST                 LAST                  MESSAGE
+3      2022-07-03T22:05:02Z  2022-07-03T22:06:03Z  query <n> failed
+`, text.String())
+
+	b, err := json.Marshal(r)
+	assert.NoError(t, err)
+	var decoded StatsReport
+	assert.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, *r, decoded)
+}
+
+func TestStats_BucketLocations(t *testing.T) {
+	// Parsing an offset gives every entry its own *time.Location
+	s := NewStats(time.Hour)
+	for _, text := range []string{"2022-07-03T21:30:00+05:30", "2022-07-03T21:45:00+05:30"} {
+		tm, err := time.Parse(time.RFC3339, text)
+		assert.NoError(t, err)
+		s.Add(Entry{Level: LogLevelInfo, Time: tm})
+	}
+
+	r := s.Report(0)
+	if assert.Len(t, r.Buckets, 1) {
+		assert.Equal(t, "2022-07-03T21:30:00+05:30", r.Buckets[0].Start.Format(time.RFC3339))
+		assert.Equal(t, map[string]int{"info": 2}, r.Buckets[0].Levels)
+	}
+}
@@ -0,0 +1,126 @@
+package jaglogger
+
+import (
+	"log"
+	"runtime"
+	"strings"
+)
+
+// StdLogger returns a *log.Logger from the standard library whose entries are written to l at the
+// given log level. This is useful for things like http.Server.ErrorLog that require a *log.Logger.
+func StdLogger(l Logger, level LogLevel) *log.Logger {
+	return log.New(stdLogWriter{l: outputterOf(l), level: level}, "", 0)
+}
+
+// RedirectStdLog makes the standard library's package level logger (log.Print, log.Printf, etc.)
+// write its entries to l at the given log level. The returned function restores the standard
+// library logger to its previous state.
+func RedirectStdLog(l Logger, level LogLevel) (restore func()) {
+	return redirectStdLog(l, level, false)
+}
+
+// RedirectStdLogDetectLevel works like RedirectStdLog, except that messages starting with a log
+// level keyword followed by a colon or in brackets (e.g. "ERROR: ...", "[warn] ...") are written at
+// the matching log level with the keyword removed. Other messages, including ones that merely start
+// with a keyword like "error rate is 5%", are written unchanged at the fallback log level.
+func RedirectStdLogDetectLevel(l Logger, fallback LogLevel) (restore func()) {
+	return redirectStdLog(l, fallback, true)
+}
+
+func redirectStdLog(l Logger, level LogLevel, detectLevel bool) func() {
+	prevFlags, prevPrefix, prevOutput := log.Flags(), log.Prefix(), log.Writer()
+
+	log.SetFlags(0)
+	log.SetPrefix("")
+	log.SetOutput(stdLogWriter{l: outputterOf(l), level: level, detectLevel: detectLevel})
+
+	return func() {
+		log.SetFlags(prevFlags)
+		log.SetPrefix(prevPrefix)
+		log.SetOutput(prevOutput)
+	}
+}
+
+// stdLogWriter receives the formatted entries of a standard library logger and writes them to a
+// Logger.
+type stdLogWriter struct {
+	l           outputter
+	level       LogLevel
+	detectLevel bool
+}
+
+func (w stdLogWriter) Write(p []byte) (int, error) {
+	msg := strings.TrimSuffix(string(p), "\n")
+	level := w.level
+	if w.detectLevel {
+		level, msg = detectLogLevel(msg, level)
+	}
+
+	w.l.output(stdLogCallDepth(), level, msg, "", nil)
+	return len(p), nil
+}
+
+// stdLogCallDepth returns the call depth, relative to the caller of stdLogWriter.Write, of the
+// first function outside of the log package. That function is the one that made the log call.
+func stdLogCallDepth() int {
+	pcs := make([]uintptr, 16)
+	// Skip runtime.Callers, stdLogCallDepth and stdLogWriter.Write
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	depth := 2
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "log.") || !more {
+			return depth
+		}
+		depth++
+	}
+}
+
+var levelKeywords = []struct {
+	keyword string
+	level   LogLevel
+}{
+	{"critical", LogLevelCritical},
+	{"crit", LogLevelCritical},
+	{"fatal", LogLevelCritical},
+	{"panic", LogLevelCritical},
+	{"error", LogLevelError},
+	{"err", LogLevelError},
+	{"warning", LogLevelWarning},
+	{"warn", LogLevelWarning},
+	{"notice", LogLevelNotice},
+	{"info", LogLevelInfo},
+	{"debug", LogLevelDebug},
+}
+
+// detectLogLevel looks for a log level keyword at the beginning of msg. If one is found, its log
+// level is returned along with msg stripped of the keyword. Otherwise, fallback and msg are
+// returned unchanged.
+func detectLogLevel(msg string, fallback LogLevel) (LogLevel, string) {
+	rest := msg
+	bracketed := strings.HasPrefix(rest, "[")
+	if bracketed {
+		rest = rest[1:]
+	}
+
+	for _, lk := range levelKeywords {
+		if len(rest) < len(lk.keyword) || !strings.EqualFold(rest[:len(lk.keyword)], lk.keyword) {
+			continue
+		}
+
+		after := rest[len(lk.keyword):]
+		switch {
+		case bracketed && strings.HasPrefix(after, "]"):
+			after = after[1:]
+		case !bracketed && strings.HasPrefix(after, ":"):
+			after = after[1:]
+		default:
+			continue
+		}
+		return lk.level, strings.TrimLeft(after, " ")
+	}
+
+	return fallback, msg
+}
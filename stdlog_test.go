@@ -0,0 +1,201 @@
+package jaglogger
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_logger_StdLogger(t *testing.T) {
+	type args struct {
+		level LogLevel
+		v     []any
+	}
+
+	errOutput := new(bytes.Buffer)
+	infoOutput := new(bytes.Buffer)
+	l := NewLogger(
+		LogLevelInfo,
+		SetDefaultErrorOutputsOpt([]io.Writer{errOutput}),
+		SetDefaultNonErrorOutputOpt([]io.Writer{infoOutput}),
+	)
+
+	tests := []struct {
+		name      string
+		args      args
+		output    *bytes.Buffer
+		wantMatch *regexp.Regexp
+	}{
+		{
+			name:      "Error Level",
+			args:      args{level: LogLevelError, v: []any{"test"}},
+			output:    errOutput,
+			wantMatch: regexp.MustCompile(`^\[ERROR\]\d{4}\/\d{2}\/\d{2}\s\d{2}\:\d{2}\:\d{2}\s.*\/stdlog_test\.go\:\d*\:\stest\n$`),
+		},
+		{
+			name:      "Info Level",
+			args:      args{level: LogLevelInfo, v: []any{"test"}},
+			output:    infoOutput,
+			wantMatch: regexp.MustCompile(`^\[INFO\]\d{4}\/\d{2}\/\d{2}\s\d{2}\:\d{2}\:\d{2}\s.*\/stdlog_test\.go\:\d*\:\stest\n$`),
+		},
+		{
+			name:      "Discarded Level",
+			args:      args{level: LogLevelDebug, v: []any{"test"}},
+			output:    infoOutput,
+			wantMatch: regexp.MustCompile(`^$`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.output.Reset()
+			StdLogger(l, tt.args.level).Print(tt.args.v...)
+			got := tt.output.String()
+			assert.Regexp(t, tt.wantMatch, got)
+		})
+	}
+}
+
+func TestRedirectStdLog(t *testing.T) {
+	loggerOutput := new(bytes.Buffer)
+	l := NewLogger(LogLevelDebug, SetWarningLoggerOpt(Config{Outputs: []io.Writer{loggerOutput}}))
+
+	prevOutput := log.Writer()
+	restore := RedirectStdLog(l, LogLevelWarning)
+	log.Printf("test %s", "test")
+	restore()
+
+	assert.Regexp(
+		t,
+		regexp.MustCompile(`^\[WARNING\]\d{4}\/\d{2}\/\d{2}\s\d{2}\:\d{2}\:\d{2}\s.*\/stdlog_test\.go\:\d*\:\stest\stest\n$`),
+		loggerOutput.String(),
+	)
+	assert.Equal(t, prevOutput, log.Writer())
+}
+
+func TestRedirectStdLogDetectLevel(t *testing.T) {
+	type args struct {
+		msg string
+	}
+
+	errOutput := new(bytes.Buffer)
+	infoOutput := new(bytes.Buffer)
+	l := NewLogger(
+		LogLevelDebug,
+		SetDefaultErrorOutputsOpt([]io.Writer{errOutput}),
+		SetDefaultNonErrorOutputOpt([]io.Writer{infoOutput}),
+		SetDefaultFlagsOpt(log.Lmsgprefix),
+	)
+
+	tests := []struct {
+		name       string
+		args       args
+		wantErr    string
+		wantNonErr string
+	}{
+		{
+			name:       "Colon Keyword",
+			args:       args{msg: "ERROR: test"},
+			wantErr:    "[ERROR]test\n",
+			wantNonErr: "",
+		},
+		{
+			name:       "Bracketed Keyword",
+			args:       args{msg: "[warn] test"},
+			wantErr:    "[WARNING]test\n",
+			wantNonErr: "",
+		},
+		{
+			name:       "Space Keyword",
+			args:       args{msg: "Debug mode enabled"},
+			wantErr:    "",
+			wantNonErr: "[NOTICE]Debug mode enabled\n",
+		},
+		{
+			name:       "Keyword In Sentence",
+			args:       args{msg: "error rate is 5%"},
+			wantErr:    "",
+			wantNonErr: "[NOTICE]error rate is 5%\n",
+		},
+		{
+			name:       "No Keyword",
+			args:       args{msg: "information test"},
+			wantErr:    "",
+			wantNonErr: "[NOTICE]information test\n",
+		},
+	}
+
+	restore := RedirectStdLogDetectLevel(l, LogLevelNotice)
+	defer restore()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errOutput.Reset()
+			infoOutput.Reset()
+			log.Print(tt.args.msg)
+			assert.Equal(t, tt.wantErr, errOutput.String())
+			assert.Equal(t, tt.wantNonErr, infoOutput.String())
+		})
+	}
+}
+
+// wrappedLogger is a Logger implemented outside of this package, which doesn't implement outputter.
+type wrappedLogger struct {
+	Logger
+}
+
+func TestRedirectStdLogDetectLevel_Wrapped(t *testing.T) {
+	errOutput := new(bytes.Buffer)
+	infoOutput := new(bytes.Buffer)
+	l := wrappedLogger{NewLogger(
+		LogLevelDebug,
+		SetDefaultErrorOutputsOpt([]io.Writer{errOutput}),
+		SetDefaultNonErrorOutputOpt([]io.Writer{infoOutput}),
+		SetDefaultFlagsOpt(log.Lmsgprefix),
+	)}
+
+	restore := RedirectStdLogDetectLevel(l, LogLevelInfo)
+	defer restore()
+
+	log.Print("ERROR: failed")
+	log.Print("error rate is 5%")
+	assert.Equal(t, "[ERROR]failed\n", errOutput.String())
+	assert.Equal(t, "[INFO]error rate is 5%\n", infoOutput.String())
+}
+
+// fieldsLogger is a Logger implemented outside of this package that has a With method.
+type fieldsLogger struct {
+	wrappedLogger
+	fields []any
+}
+
+func (l fieldsLogger) With(keysAndValues ...any) Logger {
+	l.fields = append(l.fields, keysAndValues...)
+	return l
+}
+
+func TestWrappedLogger_Functions(t *testing.T) {
+	loggerOutput := new(bytes.Buffer)
+	l := wrappedLogger{NewLogger(
+		LogLevelInfo,
+		SetDefaultErrorOutputsOpt([]io.Writer{loggerOutput}),
+		SetDefaultNonErrorOutputOpt([]io.Writer{loggerOutput}),
+		SetDefaultFlagsOpt(log.Lmsgprefix),
+	)}
+
+	w := Writer(l, LogLevelNotice)
+	io.WriteString(w, "first\nsecond")
+	w.Close()
+	V(l, 1).Info("dropped")
+	assert.NotPanics(t, func() {
+		defer Recover(l, RecoverSwallowOpt())
+		panic("boom")
+	})
+
+	assert.Equal(t, l, With(l, "a", 1))
+	assert.Equal(t, fieldsLogger{wrappedLogger: l, fields: []any{"a", 1}}, With(fieldsLogger{wrappedLogger: l}, "a", 1))
+	assert.Regexp(t, `^\[NOTICE\]first\n\[NOTICE\]second\n\[CRITICAL\]panic: boom\ngithub\.com/williabk198/jaglogger\.TestWrappedLogger_Functions\.func\d+\n`, loggerOutput.String())
+}
@@ -0,0 +1,312 @@
+package jaglogger
+
+import (
+	"bufio"
+	"io"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TextParser parses entries written in the FormatText format. For the entries to be parsed correctly,
+// its properties need to match the settings of the logger that wrote them. The zero value matches
+// the defaults of NewLogger.
+type TextParser struct {
+	// Prefixes holds the prefix of each log level. Log levels without one use the default prefix.
+	Prefixes map[LogLevel]string
+	// Flags are the log flags used by the logger. Defaults to log.Ldate|log.Ltime|log.Llongfile.
+	Flags int
+	// Caller is the CallerFormat used by the logger. Like Config.Caller, it takes the place of the
+	// log.Llongfile and log.Lshortfile flags when set.
+	Caller CallerFormat
+	// TimeFormat is the layout set with SetTimeFormatOpt, if any.
+	TimeFormat string
+	// Location is the time zone timestamps were written in when the log.LUTC flag isn't set.
+	// Defaults to time.Local.
+	Location *time.Location
+}
+
+func (p TextParser) flags() int {
+	if p.Flags == 0 {
+		return log.Ldate | log.Ltime | log.Llongfile
+	}
+	return p.Flags
+}
+
+func (p TextParser) caller() CallerFormat {
+	if p.Caller == 0 {
+		return callerFormatFromFlags(p.flags())
+	}
+	return p.Caller
+}
+
+func (p TextParser) location() *time.Location {
+	switch {
+	case p.flags()&log.LUTC != 0:
+		return time.UTC
+	case p.Location != nil:
+		return p.Location
+	default:
+		return time.Local
+	}
+}
+
+// Parse parses a single line. Messages that span multiple lines can only be parsed by a TextScanner,
+// since the lines after the first one are indistinguishable from regular text. Since the fields that
+// follow the message can't reliably be told apart from the message either, they're left in the message.
+func (p TextParser) Parse(line string) (Entry, error) {
+	var e Entry
+	rest := line
+	flags := p.flags()
+
+	if flags&log.Lmsgprefix == 0 {
+		level, n := p.matchPrefix(rest)
+		if n < 0 {
+			return e, &ParseError{Msg: "missing log level prefix"}
+		}
+		e.Level, rest = level, rest[n:]
+	}
+
+	if flags&(log.Ldate|log.Ltime|log.Lmicroseconds) != 0 {
+		t, n, ok := p.parseTime(rest)
+		if !ok {
+			return e, &ParseError{Msg: "missing or invalid timestamp"}
+		}
+		e.Time, rest = t, rest[n:]
+	}
+
+	// Entries without a caller, like the ones reporting what Sampling, RateLimit and Dedup left out,
+	// are written without one
+	if format := p.caller(); format&CallerDisabled == 0 {
+		if c, n, ok := parseTextCaller(rest, format); ok {
+			e.Caller, rest = c, rest[n:]
+		}
+	}
+
+	if flags&log.Lmsgprefix != 0 {
+		level, n := p.matchPrefix(rest)
+		if n < 0 {
+			return e, &ParseError{Msg: "missing log level prefix"}
+		}
+		e.Level, rest = level, rest[n:]
+	}
+
+	e.Message = rest
+	return e, nil
+}
+
+// matchPrefix returns the log level whose prefix s starts with, and the length of that prefix.
+// When prefixes overlap, the longest match wins. A length of -1 is returned if there's no match.
+func (p TextParser) matchPrefix(s string) (LogLevel, int) {
+	type levelPrefix struct {
+		level  LogLevel
+		prefix string
+	}
+
+	prefixes := make([]levelPrefix, 0, LogLevelCritical)
+	for level := LogLevelDebug; level <= LogLevelCritical; level++ {
+		prefix, ok := p.Prefixes[level]
+		if !ok || prefix == "" {
+			prefix = level.String()
+		}
+		prefixes = append(prefixes, levelPrefix{level: level, prefix: prefix})
+	}
+	sort.SliceStable(prefixes, func(i, j int) bool {
+		return len(prefixes[i].prefix) > len(prefixes[j].prefix)
+	})
+
+	for _, lp := range prefixes {
+		if strings.HasPrefix(s, lp.prefix) {
+			return lp.level, len(lp.prefix)
+		}
+	}
+	return 0, -1
+}
+
+// referenceTime is used to find out how many spaces a time format produces.
+var referenceTime = time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+
+// parseTime parses the timestamp at the start of s, and returns it along with the length of the
+// timestamp including the space that follows it.
+func (p TextParser) parseTime(s string) (time.Time, int, bool) {
+	if p.TimeFormat != "" {
+		// The timestamp ends at the first space that isn't part of the format
+		spaces := strings.Count(string(appendTime(nil, referenceTime, p.TimeFormat)), " ")
+		end := 0
+		for i := 0; i <= spaces; i++ {
+			n := strings.IndexByte(s[end:], ' ')
+			if n < 0 {
+				return time.Time{}, 0, false
+			}
+			end += n + 1
+		}
+		t, ok := parseTimeFormat(s[:end-1], p.TimeFormat, p.location())
+		return t, end, ok
+	}
+
+	var layouts []string
+	flags := p.flags()
+	if flags&log.Ldate != 0 {
+		layouts = append(layouts, "2006/01/02")
+	}
+	if flags&log.Lmicroseconds != 0 {
+		layouts = append(layouts, "15:04:05.000000")
+	} else if flags&log.Ltime != 0 {
+		layouts = append(layouts, "15:04:05")
+	}
+	layout := strings.Join(layouts, " ")
+
+	if len(s) <= len(layout) || s[len(layout)] != ' ' {
+		return time.Time{}, 0, false
+	}
+	t, err := time.ParseInLocation(layout, s[:len(layout)], p.location())
+	return t, len(layout) + 1, err == nil
+}
+
+// parseTimeFormat parses s, which was formatted by appendTime with the given format.
+func parseTimeFormat(s, format string, loc *time.Location) (time.Time, bool) {
+	var unit time.Duration
+	switch format {
+	case TimeFormatUnix:
+		unit = time.Second
+	case TimeFormatUnixMilli:
+		unit = time.Millisecond
+	case TimeFormatUnixMicro:
+		unit = time.Microsecond
+	case TimeFormatUnixNano:
+		unit = time.Nanosecond
+	default:
+		t, err := time.ParseInLocation(format, s, loc)
+		return t, err == nil
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(0, 0).Add(time.Duration(n) * unit), true
+}
+
+var (
+	textFileCallerPattern         = regexp.MustCompile(`^(.*?):(\d+): `)
+	textFileFunctionCallerPattern = regexp.MustCompile(`^(.*?):(\d+) (\S+): `)
+	textFunctionCallerPattern     = regexp.MustCompile(`^(\S+): `)
+)
+
+// parseTextCaller parses the caller at the start of s, and returns it along with the length of the
+// caller including the ": " that follows it.
+func parseTextCaller(s string, format CallerFormat) (Caller, int, bool) {
+	var c Caller
+	var match []string
+	switch {
+	case format&callerFileFormats != 0 && format&CallerFunction != 0:
+		match = textFileFunctionCallerPattern.FindStringSubmatch(s)
+		if match != nil {
+			c.Function = match[3]
+		}
+	case format&callerFileFormats != 0:
+		match = textFileCallerPattern.FindStringSubmatch(s)
+	default:
+		match = textFunctionCallerPattern.FindStringSubmatch(s)
+		if match != nil {
+			c.Function = match[1]
+		}
+	}
+	if match == nil {
+		return c, 0, false
+	}
+
+	if format&callerFileFormats != 0 {
+		c.File = match[1]
+		c.Line, _ = strconv.Atoi(match[2])
+	}
+	// The log package, and therefore jaglogger, writes ???:0 when the caller isn't known
+	c.Defined = c.File != "???" && c.Function != "???"
+	if !c.Defined {
+		c = Caller{}
+	}
+	return c, len(match[0]), true
+}
+
+// TextScanner is a Scanner for entries written in the FormatText format. Lines that don't start with
+// an entry header are considered to be a continuation of the previous entry's message, so messages that
+// span multiple lines are read as a whole. Because of that, an entry is only available once the line
+// after it has been read, or the end of the input has been reached.
+type TextScanner struct {
+	s      *bufio.Scanner
+	parser TextParser
+
+	lineNum int
+	entry   Entry
+	err     error
+	text    string
+
+	// The line read ahead of the current entry, to find where the current entry's message ends
+	hasNext   bool
+	next      string
+	nextEntry Entry
+	nextErr   error
+}
+
+// NewTextScanner returns a TextScanner that reads from r, and uses parser to parse the entries.
+func NewTextScanner(r io.Reader, parser TextParser) *TextScanner {
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, maxScanLineLength)
+	return &TextScanner{s: s, parser: parser}
+}
+
+// Scan advances to the next entry, which is then available through Entry. It returns false once
+// there are no more entries or reading fails.
+func (s *TextScanner) Scan() bool {
+	for !s.hasNext || s.next == "" {
+		if !s.readLine() {
+			return false
+		}
+	}
+
+	s.entry, s.err, s.text = s.nextEntry, s.nextErr, s.next
+	if pe, ok := s.err.(*ParseError); ok {
+		pe.Line = s.lineNum
+	}
+	s.hasNext = false
+
+	// A line that isn't a header continues the current message
+	for s.readLine() && s.nextErr != nil {
+		if s.err == nil {
+			s.entry.Message += "\n" + s.next
+		}
+		s.text += "\n" + s.next
+		s.hasNext = false
+	}
+	return true
+}
+
+func (s *TextScanner) readLine() bool {
+	if !s.s.Scan() {
+		return false
+	}
+	s.lineNum++
+	s.next = s.s.Text()
+	s.nextEntry, s.nextErr = s.parser.Parse(s.next)
+	s.hasNext = true
+	return true
+}
+
+// Entry returns the most recent entry read by Scan. If its first line couldn't be parsed, a
+// *ParseError is returned.
+func (s *TextScanner) Entry() (Entry, error) {
+	return s.entry, s.err
+}
+
+// Text returns the lines of the most recent entry read by Scan.
+func (s *TextScanner) Text() string {
+	return s.text
+}
+
+// Err returns the first error encountered while reading, other than io.EOF.
+func (s *TextScanner) Err() error {
+	return s.s.Err()
+}
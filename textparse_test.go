@@ -0,0 +1,221 @@
+package jaglogger
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTextParser_Parse(t *testing.T) {
+	type args struct {
+		line string
+	}
+
+	est := time.FixedZone("EST", -5*60*60)
+
+	tests := []struct {
+		name    string
+		parser  TextParser
+		args    args
+		want    Entry
+		wantErr string
+	}{
+		{
+			name:   "Defaults",
+			parser: TextParser{Location: est},
+			args:   args{line: "[INFO]2022/07/03 22:05:03 /path/to/workspace/main.go:8: test: test"},
+			want: Entry{
+				Level:   LogLevelInfo,
+				Time:    time.Date(2022, time.July, 3, 22, 5, 3, 0, est),
+				Caller:  Caller{File: "/path/to/workspace/main.go", Line: 8, Defined: true},
+				Message: "test: test",
+			},
+		},
+		{
+			name:   "Microseconds UTC Short File",
+			parser: TextParser{Flags: log.Ldate | log.Lmicroseconds | log.LUTC | log.Lshortfile},
+			args:   args{line: "[ERROR]2022/07/04 03:05:03.123456 main.go:8: test"},
+			want: Entry{
+				Level:   LogLevelError,
+				Time:    time.Date(2022, time.July, 4, 3, 5, 3, 123456000, time.UTC),
+				Caller:  Caller{File: "main.go", Line: 8, Defined: true},
+				Message: "test",
+			},
+		},
+		{
+			name:   "Message Prefix",
+			parser: TextParser{Flags: log.Ltime | log.Lmsgprefix | log.Lshortfile, Location: time.UTC},
+			args:   args{line: "22:05:03 main.go:8: [WARNING]test"},
+			want: Entry{
+				Level:   LogLevelWarning,
+				Time:    time.Date(0, time.January, 1, 22, 5, 3, 0, time.UTC),
+				Caller:  Caller{File: "main.go", Line: 8, Defined: true},
+				Message: "test",
+			},
+		},
+		{
+			name:   "Custom Prefixes",
+			parser: TextParser{Flags: log.Lmsgprefix, Prefixes: map[LogLevel]string{LogLevelError: "E ", LogLevelCritical: "E! "}},
+			args:   args{line: "E! test"},
+			want:   Entry{Level: LogLevelCritical, Message: "test"},
+		},
+		{
+			name:   "Caller Function",
+			parser: TextParser{Flags: log.Lmsgprefix, Caller: CallerModuleFile | CallerFunction},
+			args:   args{line: "internal/db/conn.go:23 example.com/app/internal/db.(*Conn).Query: [DEBUG]test"},
+			want: Entry{
+				Level:   LogLevelDebug,
+				Caller:  Caller{File: "internal/db/conn.go", Line: 23, Function: "example.com/app/internal/db.(*Conn).Query", Defined: true},
+				Message: "test",
+			},
+		},
+		{
+			name:   "Unknown Caller",
+			parser: TextParser{Flags: log.Lshortfile},
+			args:   args{line: "[INFO]???:0: test"},
+			want:   Entry{Level: LogLevelInfo, Message: "test"},
+		},
+		{
+			name:   "Time Format",
+			parser: TextParser{Flags: log.Ldate, TimeFormat: time.RFC1123},
+			args:   args{line: "[INFO]Sun, 03 Jul 2022 22:05:03 UTC test"},
+			want:   Entry{Level: LogLevelInfo, Time: time.Date(2022, time.July, 3, 22, 5, 3, 0, time.UTC), Message: "test"},
+		},
+		{
+			name:   "Unix Time Format",
+			parser: TextParser{Flags: log.Ldate, TimeFormat: TimeFormatUnixMilli},
+			args:   args{line: "[INFO]1656903903123 test"},
+			want:   Entry{Level: LogLevelInfo, Time: time.UnixMilli(1656903903123), Message: "test"},
+		},
+		{
+			name:    "Missing Prefix",
+			parser:  TextParser{},
+			args:    args{line: "test"},
+			wantErr: "jaglogger: missing log level prefix",
+		},
+		{
+			name:    "Invalid Timestamp",
+			parser:  TextParser{},
+			args:    args{line: "[INFO]2022/07/03 test"},
+			want:    Entry{Level: LogLevelInfo},
+			wantErr: "jaglogger: missing or invalid timestamp",
+		},
+		{
+			name:   "Missing Caller",
+			parser: TextParser{Flags: log.Lshortfile},
+			args:   args{line: "[INFO]message repeated 2 times repeated=2"},
+			want:   Entry{Level: LogLevelInfo, Message: "message repeated 2 times repeated=2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.parser.Parse(tt.args.line)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.True(t, tt.want.Time.Equal(got.Time), "want time %s, got %s", tt.want.Time, got.Time)
+			tt.want.Time, got.Time = time.Time{}, time.Time{}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestTextScanner(t *testing.T) {
+	input := "not an entry\n" +
+		"still not an entry\n" +
+		"[INFO]2022/07/03 22:05:03 main.go:8: first\n" +
+		"[ERROR]2022/07/03 22:05:04 main.go:9: second\n" +
+		"  continued\n" +
+		"\n" +
+		"  after a blank line\n" +
+		"[DEBUG]2022/07/03 22:05:05 main.go:10: third\n"
+
+	s := NewTextScanner(strings.NewReader(input), TextParser{Flags: log.Ldate | log.Ltime | log.Lshortfile})
+
+	assert.True(t, s.Scan())
+	_, err := s.Entry()
+	assert.EqualError(t, err, "jaglogger: line 1: missing log level prefix")
+	assert.Equal(t, "not an entry\nstill not an entry", s.Text())
+
+	assert.True(t, s.Scan())
+	e, err := s.Entry()
+	assert.NoError(t, err)
+	assert.Equal(t, LogLevelInfo, e.Level)
+	assert.Equal(t, "first", e.Message)
+
+	assert.True(t, s.Scan())
+	e, err = s.Entry()
+	assert.NoError(t, err)
+	assert.Equal(t, LogLevelError, e.Level)
+	assert.Equal(t, "second\n  continued\n\n  after a blank line", e.Message)
+	assert.Equal(t, Caller{File: "main.go", Line: 9, Defined: true}, e.Caller)
+
+	assert.True(t, s.Scan())
+	e, err = s.Entry()
+	assert.NoError(t, err)
+	assert.Equal(t, LogLevelDebug, e.Level)
+	assert.Equal(t, "third", e.Message)
+
+	assert.False(t, s.Scan())
+	assert.NoError(t, s.Err())
+}
+
+func TestTextScanner_RoundTrip(t *testing.T) {
+	clock := func() time.Time {
+		return time.Date(2022, time.July, 3, 22, 5, 3, 123456000, time.UTC)
+	}
+
+	tests := []struct {
+		name   string
+		flags  int
+		prefix string
+	}{
+		{name: "Default Flags", flags: log.Ldate | log.Ltime | log.Llongfile},
+		{name: "Microseconds UTC", flags: log.Ldate | log.Ltime | log.Lmicroseconds | log.LUTC | log.Lshortfile},
+		{name: "Message Prefix", flags: log.Ltime | log.Lshortfile | log.Lmsgprefix, prefix: "error: "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loggerOutput := new(bytes.Buffer)
+			l := NewLogger(
+				LogLevelDebug,
+				SetClockOpt(clock),
+				SetTimeLocationOpt(time.UTC),
+				SetDefaultFlagsOpt(tt.flags),
+				SetDefaultErrorOutputsOpt([]io.Writer{loggerOutput}),
+				SetDefaultNonErrorOutputOpt([]io.Writer{loggerOutput}),
+				SetErrorLoggerOpt(Config{Prefix: tt.prefix}),
+			)
+			l.Info("first")
+			l.Error("second\nline")
+
+			s := NewTextScanner(loggerOutput, TextParser{
+				Prefixes: map[LogLevel]string{LogLevelError: tt.prefix},
+				Flags:    tt.flags,
+				Location: time.UTC,
+			})
+			var got []Entry
+			for s.Scan() {
+				e, err := s.Entry()
+				assert.NoError(t, err)
+				got = append(got, e)
+			}
+
+			if assert.Len(t, got, 2) {
+				assert.Equal(t, LogLevelInfo, got[0].Level)
+				assert.Equal(t, "first", got[0].Message)
+				assert.Equal(t, LogLevelError, got[1].Level)
+				assert.Equal(t, "second\nline", got[1].Message)
+				assert.Equal(t, "textparse_test.go", got[1].Caller.File[strings.LastIndex(got[1].Caller.File, "/")+1:])
+				assert.Equal(t, clock().Truncate(time.Second).Format("15:04:05"), got[1].Time.Format("15:04:05"))
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package jaglogger
+
+import (
+	"fmt"
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Verbosity holds the verbosity used by V: a global level, and per-file overrides in the
+// format of glog's -vmodule flag. Both can be changed at any time, e.g. from an admin endpoint, and
+// the change applies to every Logger that it was given to with SetVerbosityOpt. The zero value has
+// a level of 0 and no overrides.
+type Verbosity struct {
+	level int32
+	// modules holds a *vmodule, which is replaced as a whole by SetVModule
+	modules atomic.Value
+}
+
+// NewVerbosity returns a Verbosity with the given global level.
+func NewVerbosity(level int) *Verbosity {
+	v := &Verbosity{}
+	v.SetLevel(level)
+	return v
+}
+
+// Level returns the global verbosity level.
+func (v *Verbosity) Level() int {
+	return int(atomic.LoadInt32(&v.level))
+}
+
+// SetLevel sets the global verbosity level. Calls to V with a level up to it are enabled everywhere.
+func (v *Verbosity) SetLevel(level int) {
+	atomic.StoreInt32(&v.level, int32(level))
+}
+
+// VModule returns the overrides set with SetVModule.
+func (v *Verbosity) VModule() string {
+	if m := v.vmodule(); m != nil {
+		return m.spec
+	}
+	return ""
+}
+
+// SetVModule sets the verbosity level of the files matching patterns. Like glog's, an override can
+// only raise the level of a file above the global level, since V checks the global level first. spec
+// is a comma-separated list of pattern=level pairs, like "db*=4,http/server=2". A pattern
+// without a slash is matched against the name of the file without its .go extension. A pattern with
+// slashes is matched against as many of the last elements of the file's path. Patterns use the
+// syntax of path.Match, and the first one that matches a file sets its level. An empty spec removes
+// all the overrides.
+func (v *Verbosity) SetVModule(spec string) error {
+	m := &vmodule{spec: spec, cache: map[uintptr]vmoduleSite{}}
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		pattern, levelText, ok := strings.Cut(pair, "=")
+		if !ok || pattern == "" {
+			return fmt.Errorf("jaglogger: invalid vmodule %q: missing pattern or level", pair)
+		}
+		level, err := strconv.Atoi(levelText)
+		if err != nil {
+			return fmt.Errorf("jaglogger: invalid vmodule level %q", pair)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("jaglogger: invalid vmodule pattern %q: %w", pattern, err)
+		}
+		m.patterns = append(m.patterns, vmodulePattern{pattern: pattern, level: level})
+	}
+
+	if len(m.patterns) == 0 {
+		m = &vmodule{}
+	}
+	v.modules.Store(m)
+	return nil
+}
+
+func (v *Verbosity) vmodule() *vmodule {
+	m, _ := v.modules.Load().(*vmodule)
+	return m
+}
+
+// enabled reports whether V(level) is enabled for its caller. The caller of V is reported the same
+// way as the caller of an entry, so callerSkip and helpers apply.
+func (v *Verbosity) enabled(level, callerSkip int, helpers *helperFuncs) bool {
+	if level <= 0 {
+		return true
+	}
+	if v == nil {
+		return false
+	}
+	if level <= v.Level() {
+		return true
+	}
+	m := v.vmodule()
+	if m == nil || len(m.patterns) == 0 {
+		return false
+	}
+
+	// Skip enabled and V
+	if helpers.any() {
+		fileLevel, matched := m.fileLevel(lookupCaller(2+callerSkip, helpers).File)
+		return matched && level <= fileLevel
+	}
+	var pcs [1]uintptr
+	// Skip runtime.Callers, enabled and V
+	if runtime.Callers(3+callerSkip, pcs[:]) == 0 {
+		return false
+	}
+	m.mu.RLock()
+	site, ok := m.cache[pcs[0]]
+	m.mu.RUnlock()
+	if !ok {
+		frame, _ := runtime.CallersFrames([]uintptr{pcs[0]}).Next()
+		site.level, site.matched = m.fileLevel(frame.File)
+		m.mu.Lock()
+		m.cache[pcs[0]] = site
+		m.mu.Unlock()
+	}
+	// Without a match, the global level applies, which was already checked
+	return site.matched && level <= site.level
+}
+
+// vmodule is a parsed SetVModule spec, along with the levels of the call sites seen so far.
+type vmodule struct {
+	spec     string
+	patterns []vmodulePattern
+	mu       sync.RWMutex
+	// cache maps the program counter of a call to V to the override of its file
+	cache map[uintptr]vmoduleSite
+}
+
+// vmoduleSite is the override of the file of a call to V. The global level isn't cached, since it
+// can change.
+type vmoduleSite struct {
+	level   int
+	matched bool
+}
+
+type vmodulePattern struct {
+	pattern string
+	level   int
+}
+
+// fileLevel returns the level of the first pattern matching file, and whether there is one.
+func (m *vmodule) fileLevel(file string) (int, bool) {
+	file = strings.TrimSuffix(file, ".go")
+	for _, p := range m.patterns {
+		elems := strings.Count(p.pattern, "/") + 1
+		name := file
+		for i, n := len(file)-1, 0; i >= 0; i-- {
+			if file[i] == '/' {
+				n++
+				if n == elems {
+					name = file[i+1:]
+					break
+				}
+			}
+		}
+		if ok, _ := path.Match(p.pattern, name); ok {
+			return p.level, true
+		}
+	}
+	return 0, false
+}
+
+// V returns l if the verbosity level of the caller of V is at least level. That's the global level
+// of the Verbosity set with SetVerbosityOpt, or the level of the first override set with SetVModule
+// that matches the caller's file if it's higher. When it isn't enabled, V returns a Logger like Nop,
+// which makes a disabled call cheap, and drops entries of every log level. Without a Verbosity, only
+// levels up to 0 are enabled. Loggers from outside of this package get their V(int) Logger method
+// called, if they have one.
+func V(l Logger, level int) Logger {
+	switch l := l.(type) {
+	case logger:
+		if !l.verbosity.enabled(level, l.callerSkip, l.helpers) {
+			return nopLogger{}
+		}
+		return l
+	case interface{ V(int) Logger }:
+		return l.V(level)
+	}
+	if level > 0 {
+		return nopLogger{}
+	}
+	return l
+}
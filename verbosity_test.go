@@ -0,0 +1,271 @@
+package jaglogger
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerbosity_SetVModule(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    []vmodulePattern
+		wantErr bool
+	}{
+		{
+			name: "Empty",
+			spec: "",
+		},
+		{
+			name: "Patterns",
+			spec: "db*=4, http/server=2,",
+			want: []vmodulePattern{{pattern: "db*", level: 4}, {pattern: "http/server", level: 2}},
+		},
+		{
+			name:    "Missing Level",
+			spec:    "db*",
+			wantErr: true,
+		},
+		{
+			name:    "Missing Pattern",
+			spec:    "=2",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid Level",
+			spec:    "db=high",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid Pattern",
+			spec:    "db[=2",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &Verbosity{}
+			err := v.SetVModule(tt.spec)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Equal(t, "", v.VModule())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.spec, v.VModule())
+			assert.Equal(t, tt.want, v.vmodule().patterns)
+		})
+	}
+}
+
+func Test_vmodule_fileLevel(t *testing.T) {
+	v := &Verbosity{}
+	assert.NoError(t, v.SetVModule("db*=4,http/server=2,*/client/*=3,main=1"))
+
+	tests := []struct {
+		name string
+		file string
+		want int
+	}{
+		{name: "Basename", file: "/src/app/db/dbpool.go", want: 4},
+		{name: "Path", file: "/src/app/http/server.go", want: 2},
+		{name: "Path Doesn't Match Basename", file: "/src/app/server.go", want: -1},
+		{name: "Pattern Path", file: "/src/app/api/client/retry.go", want: 3},
+		{name: "First Match Wins", file: "/src/app/db/main.go", want: 1},
+		{name: "Relative", file: "main.go", want: 1},
+		{name: "No Match", file: "/src/app/cache.go", want: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level, matched := v.vmodule().fileLevel(tt.file)
+			if !matched {
+				level = -1
+			}
+			assert.Equal(t, tt.want, level)
+		})
+	}
+}
+
+func logVThroughHelper(l Logger, level int, msg string) {
+	Helper(l)
+	V(l, level).Info(msg)
+}
+
+func Test_logger_V(t *testing.T) {
+	tests := []struct {
+		name    string
+		level   int
+		vmodule string
+		log     func(l Logger)
+		want    string
+	}{
+		{
+			name: "Global Level",
+			log: func(l Logger) {
+				V(l, 0).Info("zero")
+				V(l, 1).Info("one")
+			},
+			want: "[INFO]zero\n",
+		},
+		{
+			name:  "Applies To Every Level",
+			level: 1,
+			log: func(l Logger) {
+				V(l, 1).Error("error")
+				V(l, 2).Error("dropped")
+				V(l, 2).Debug("debug")
+			},
+			want: "[ERROR]error\n",
+		},
+		{
+			name:    "VModule",
+			level:   1,
+			vmodule: "verbosity_test=3",
+			log: func(l Logger) {
+				V(l, 3).Info("three")
+				V(l, 4).Info("four")
+			},
+			want: "[INFO]three\n",
+		},
+		{
+			name:    "VModule Doesn't Lower Level",
+			level:   2,
+			vmodule: "verbosity*=1",
+			log: func(l Logger) {
+				V(l, 2).Info("two")
+			},
+			want: "[INFO]two\n",
+		},
+		{
+			name:    "VModule Other File",
+			level:   1,
+			vmodule: "jaglogger=3",
+			log: func(l Logger) {
+				V(l, 2).Info("two")
+			},
+			want: "",
+		},
+		{
+			name:    "Helper",
+			vmodule: "verbosity_test=2",
+			log: func(l Logger) {
+				logVThroughHelper(l, 2, "helper")
+			},
+			want: "[INFO]helper\n",
+		},
+		{
+			name:    "Derived Logger",
+			vmodule: "verbosity_test=2",
+			log: func(l Logger) {
+				V(With(l, "key", "value"), 2).Info("with")
+			},
+			want: "[INFO]with key=value\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerbosity(tt.level)
+			assert.NoError(t, v.SetVModule(tt.vmodule))
+			loggerOutput := new(bytes.Buffer)
+			config := Config{Outputs: []io.Writer{loggerOutput}, Flags: log.Lmsgprefix}
+			l := NewLogger(
+				LogLevelDebug,
+				SetVerbosityOpt(v),
+				SetErrorLoggerOpt(config),
+				SetInfoLoggerOpt(config),
+				SetDebugLoggerOpt(config),
+			)
+
+			tt.log(l)
+			assert.Equal(t, tt.want, loggerOutput.String())
+		})
+	}
+}
+
+func Test_logger_VRuntimeChange(t *testing.T) {
+	v := &Verbosity{}
+	loggerOutput := new(bytes.Buffer)
+	l := NewLogger(LogLevelInfo, SetVerbosityOpt(v), SetInfoLoggerOpt(Config{Outputs: []io.Writer{loggerOutput}, Flags: log.Lmsgprefix}))
+
+	logV := func(msg string) {
+		V(l, 2).Info(msg)
+	}
+	logV("before")
+	v.SetLevel(2)
+	logV("level")
+	v.SetLevel(0)
+	assert.NoError(t, v.SetVModule("verbosity_test=2"))
+	logV("vmodule")
+	assert.NoError(t, v.SetVModule(""))
+	logV("after")
+
+	assert.Equal(t, "[INFO]level\n[INFO]vmodule\n", loggerOutput.String())
+}
+
+func Test_logger_VLowerLevelAfterCachedMiss(t *testing.T) {
+	v := NewVerbosity(1)
+	assert.NoError(t, v.SetVModule("other=4"))
+	loggerOutput := new(bytes.Buffer)
+	l := NewLogger(LogLevelInfo, SetVerbosityOpt(v), SetInfoLoggerOpt(Config{Outputs: []io.Writer{loggerOutput}, Flags: log.Lmsgprefix}))
+
+	// Every entry is logged by the same call site, which is cached by the first one
+	steps := []struct {
+		global int
+		level  int
+		msg    string
+	}{
+		{global: 1, level: 2, msg: "cached"},
+		{global: 0, level: 1, msg: "lowered"},
+		{global: 2, level: 2, msg: "raised"},
+	}
+	for _, step := range steps {
+		v.SetLevel(step.global)
+		V(l, step.level).Info(step.msg)
+	}
+
+	assert.Equal(t, "[INFO]raised\n", loggerOutput.String())
+}
+
+func Test_logger_VNoVerbosity(t *testing.T) {
+	loggerOutput := new(bytes.Buffer)
+	l := NewLogger(LogLevelInfo, SetInfoLoggerOpt(Config{Outputs: []io.Writer{loggerOutput}, Flags: log.Lmsgprefix}))
+
+	V(l, 0).Info("zero")
+	V(l, 1).Info("one")
+	assert.Equal(t, "[INFO]zero\n", loggerOutput.String())
+}
+
+func Test_logger_VDisabledAllocs(t *testing.T) {
+	v := NewVerbosity(1)
+	assert.NoError(t, v.SetVModule("other=4"))
+	l := NewLogger(LogLevelInfo, SetVerbosityOpt(v), SetInfoLoggerOpt(Config{Outputs: []io.Writer{io.Discard}}))
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = V(l, 3)
+	})
+	assert.Zero(t, allocs)
+}
+
+func Benchmark_logger_V(b *testing.B) {
+	v := NewVerbosity(1)
+	l := NewLogger(LogLevelInfo, SetVerbosityOpt(v), SetInfoLoggerOpt(Config{Outputs: []io.Writer{io.Discard}, Caller: CallerDisabled}))
+
+	b.Run("Disabled", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			V(l, 2).Infof("test %s", "value")
+		}
+	})
+	b.Run("Disabled VModule", func(b *testing.B) {
+		assert.NoError(b, v.SetVModule("other=4"))
+		defer v.SetVModule("")
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			V(l, 2).Infof("test %s", "value")
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package jaglogger
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+)
+
+// maxWriterLineLength is the maximum length of a line written through a Writer. Longer lines are
+// split into multiple log entries.
+const maxWriterLineLength = 64 * 1024
+
+// ErrWriterClosed is returned when writing to a Writer that has already been closed.
+var ErrWriterClosed = errors.New("jaglogger: write to closed writer")
+
+// Writer returns an io.WriteCloser that logs each line written to it as an entry at the given log
+// level. Partial lines are buffered until a newline is written or the writer is closed. This makes
+// it possible to capture output such as the Stdout and Stderr of an exec.Cmd.
+func Writer(l Logger, level LogLevel) io.WriteCloser {
+	return &lineWriter{l: outputterOf(l), level: level}
+}
+
+// lineWriter splits the bytes written to it into lines and logs each one.
+type lineWriter struct {
+	mu     sync.Mutex
+	l      outputter
+	level  LogLevel
+	buf    []byte
+	closed bool
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, ErrWriterClosed
+	}
+
+	n := len(p)
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			w.buf = append(w.buf, p...)
+			break
+		}
+		w.buf = append(w.buf, p[:i]...)
+		p = p[i+1:]
+		w.flush()
+	}
+
+	// Don't let a line without a newline grow forever
+	if len(w.buf) >= maxWriterLineLength {
+		w.flushFull()
+	}
+
+	return n, nil
+}
+
+// Close logs any buffered partial line. Writing to the writer after it has been closed will
+// return ErrWriterClosed.
+func (w *lineWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	if len(w.buf) > 0 {
+		w.flush()
+	}
+	return nil
+}
+
+// flush logs the contents of the buffer, splitting it if it's too long, and empties the buffer.
+func (w *lineWriter) flush() {
+	line := bytes.TrimSuffix(w.buf, []byte{'\r'})
+	for len(line) > maxWriterLineLength {
+		w.writeLine(line[:maxWriterLineLength])
+		line = line[maxWriterLineLength:]
+	}
+	w.writeLine(line)
+	w.buf = w.buf[:0]
+}
+
+// flushFull logs the parts of the buffer that are as long as the maximum line length, and keeps the
+// rest of it, which may still be continued by the next write.
+func (w *lineWriter) flushFull() {
+	n := 0
+	for ; len(w.buf)-n >= maxWriterLineLength; n += maxWriterLineLength {
+		w.writeLine(w.buf[n : n+maxWriterLineLength])
+	}
+	w.buf = w.buf[:copy(w.buf, w.buf[n:])]
+}
+
+func (w *lineWriter) writeLine(line []byte) {
+	// Report the caller of Write or Close, which is 3 frames up from here.
+	w.l.output(4, w.level, string(line), "", nil)
+}
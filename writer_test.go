@@ -0,0 +1,83 @@
+package jaglogger
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_logger_Writer(t *testing.T) {
+	type args struct {
+		writes []string
+	}
+
+	longLine := strings.Repeat("a", maxWriterLineLength)
+
+	tests := []struct {
+		name          string
+		args          args
+		wantBeforeEnd string
+		want          string
+	}{
+		{
+			name:          "Single Line",
+			args:          args{writes: []string{"test\n"}},
+			wantBeforeEnd: "[INFO]test\n",
+			want:          "[INFO]test\n",
+		},
+		{
+			name:          "Partial Writes",
+			args:          args{writes: []string{"te", "st\ntest", " test\r\n", "end"}},
+			wantBeforeEnd: "[INFO]test\n[INFO]test test\n",
+			want:          "[INFO]test\n[INFO]test test\n[INFO]end\n",
+		},
+		{
+			name:          "Empty Line",
+			args:          args{writes: []string{"\n"}},
+			wantBeforeEnd: "[INFO]\n",
+			want:          "[INFO]\n",
+		},
+		{
+			name:          "Long Line",
+			args:          args{writes: []string{longLine[:10], longLine[10:] + "b\n"}},
+			wantBeforeEnd: "[INFO]" + longLine + "\n[INFO]b\n",
+			want:          "[INFO]" + longLine + "\n[INFO]b\n",
+		},
+		{
+			name:          "Long Partial Line",
+			args:          args{writes: []string{longLine, "b"}},
+			wantBeforeEnd: "[INFO]" + longLine + "\n",
+			want:          "[INFO]" + longLine + "\n[INFO]b\n",
+		},
+		{
+			name:          "Long Partial Line Past The Cap",
+			args:          args{writes: []string{longLine + "xyz", "tail\n"}},
+			wantBeforeEnd: "[INFO]" + longLine + "\n[INFO]xyztail\n",
+			want:          "[INFO]" + longLine + "\n[INFO]xyztail\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loggerOutput := new(bytes.Buffer)
+			l := NewLogger(LogLevelInfo, SetInfoLoggerOpt(Config{Outputs: []io.Writer{loggerOutput}, Flags: log.Lmsgprefix}))
+
+			w := Writer(l, LogLevelInfo)
+			for _, s := range tt.args.writes {
+				n, err := w.Write([]byte(s))
+				assert.NoError(t, err)
+				assert.Equal(t, len(s), n)
+			}
+			assert.Equal(t, tt.wantBeforeEnd, loggerOutput.String())
+
+			assert.NoError(t, w.Close())
+			assert.Equal(t, tt.want, loggerOutput.String())
+
+			_, err := w.Write([]byte("test"))
+			assert.ErrorIs(t, err, ErrWriterClosed)
+		})
+	}
+}